@@ -0,0 +1,171 @@
+package mog
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// --- Collection Admin Methods ----------------------------------------------------
+
+// CollectionOpts are the options used by CreateCollection. The zero value creates a plain collection.
+type CollectionOpts struct {
+	CappedSize      int64  // if > 0, collection is capped at this many bytes, oldest docs removed first
+	CappedMaxDocs   int64  // if > 0 (and CappedSize > 0), capped collection also limited to this many docs
+	JSONSchema      bson.M // if not nil, inserts and updates are validated against this $jsonSchema
+	CollationLocale string // if not "", default collation for queries and indexes, e.g. "en"
+	CaseInsensitive bool   // with CollationLocale, default collation ignores case
+}
+
+// CreateCollection creates collection name in mog's database using opts.
+// The collection used by mog (see SetCollection) is not changed.
+// Ex: CreateCollection("log", CollectionOpts{CappedSize: 1 << 20})
+func (mog *Mog) CreateCollection(name string, opts CollectionOpts) error {
+	createOptions := options.CreateCollection()
+	if opts.CappedSize > 0 {
+		createOptions.SetCapped(true).SetSizeInBytes(opts.CappedSize)
+		if opts.CappedMaxDocs > 0 {
+			createOptions.SetMaxDocuments(opts.CappedMaxDocs)
+		}
+	}
+	if opts.JSONSchema != nil {
+		createOptions.SetValidator(bson.M{"$jsonSchema": opts.JSONSchema})
+	}
+	if opts.CollationLocale != "" {
+		collation := &options.Collation{Locale: opts.CollationLocale}
+		if opts.CaseInsensitive {
+			collation.Strength = 2
+		}
+		createOptions.SetCollation(collation)
+	}
+	return mog.db.CreateCollection(mog.ctx, name, createOptions)
+}
+
+// CollectionInfo describes a collection or view in mog's database, see ListCollections.
+type CollectionInfo struct {
+	Name    string `bson:"name"`
+	Type    string `bson:"type"`    // "collection" or "view"
+	Options bson.M `bson:"options"` // capped, validator, collation, viewOn, pipeline, etc.
+	Info    struct {
+		ReadOnly bool `bson:"readOnly"`
+	} `bson:"info"`
+}
+
+// ListCollections returns info for all collections and views in mog's database, sorted by name.
+func (mog *Mog) ListCollections() ([]CollectionInfo, error) {
+	cursor, err := mog.db.ListCollections(mog.ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	var infos []CollectionInfo
+	if err = cursor.All(mog.ctx, &infos); err != nil {
+		return nil, err
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// DropCollection drops mog's collection, removing all its docs and indexes.
+// Dropping a collection that does not exist is not an error.
+func (mog *Mog) DropCollection() error {
+	return mog.collection.Drop(mog.ctx)
+}
+
+// RenameCollection renames mog's collection to newName (in the same database). Mog then uses newName.
+// Fails if a collection named newName exists.
+func (mog *Mog) RenameCollection(newName string) error {
+	dbName := mog.db.Name()
+	cmd := bson.D{
+		{Key: "renameCollection", Value: dbName + "." + mog.collectionName},
+		{Key: "to", Value: dbName + "." + newName},
+	}
+	err := mog.db.Client().Database("admin").RunCommand(mog.ctx, cmd).Err()
+	if err != nil {
+		return err
+	}
+	mog.SetCollection(newName)
+	return nil
+}
+
+// CollStats contains storage statistics for a collection, see CollStats.
+// Sizes are in bytes.
+type CollStats struct {
+	Count          int64            `bson:"count"`          // number of docs
+	Size           int64            `bson:"size"`           // uncompressed size of all docs
+	AvgObjSize     float64          `bson:"avgObjSize"`     // average doc size
+	StorageSize    int64            `bson:"storageSize"`    // size allocated on disk for docs
+	NumIndexes     int64            `bson:"nindexes"`       // number of indexes
+	TotalIndexSize int64            `bson:"totalIndexSize"` // size of all indexes
+	IndexSizes     map[string]int64 `bson:"indexSizes"`     // size of each index by name
+}
+
+// DBStats contains storage statistics for a database, see DBStats.
+// Sizes are in bytes.
+type DBStats struct {
+	Collections int64   `bson:"collections"`
+	Views       int64   `bson:"views"`
+	Objects     int64   `bson:"objects"` // number of docs in all collections
+	AvgObjSize  float64 `bson:"avgObjSize"`
+	DataSize    int64   `bson:"dataSize"`    // uncompressed size of all docs
+	StorageSize int64   `bson:"storageSize"` // size allocated on disk for docs
+	Indexes     int64   `bson:"indexes"`
+	IndexSize   int64   `bson:"indexSize"`
+}
+
+// CollStats returns storage statistics for mog's collection.
+func (mog *Mog) CollStats() (CollStats, error) {
+	var stats CollStats
+	pipeline := []bson.M{{"$collStats": bson.M{"storageStats": bson.M{}}}}
+	cursor, err := mog.collection.Aggregate(mog.ctx, pipeline)
+	if err != nil {
+		return stats, err
+	}
+	var results []struct {
+		StorageStats CollStats `bson:"storageStats"`
+	}
+	if err = cursor.All(mog.ctx, &results); err != nil {
+		return stats, err
+	}
+	if len(results) == 0 {
+		return stats, errors.New("no stats returned for " + mog.collectionName)
+	}
+	return results[0].StorageStats, nil
+}
+
+// DBStats returns storage statistics for mog's database.
+func (mog *Mog) DBStats() (DBStats, error) {
+	var stats DBStats
+	err := mog.db.RunCommand(mog.ctx, bson.D{{Key: "dbStats", Value: 1}}).Decode(&stats)
+	return stats, err
+}
+
+// Ping verifies the server is reachable within timeout.
+func (mog *Mog) Ping(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(mog.ctx, timeout)
+	defer cancel()
+	return mog.db.Client().Ping(ctx, readpref.Primary())
+}
+
+// Healthy verifies the server is reachable (Ping with 5 second timeout) and mog's collection exists.
+// Intended for service readiness probes.
+func (mog *Mog) Healthy() error {
+	if err := mog.Ping(5 * time.Second); err != nil {
+		return err
+	}
+	if mog.collection == nil {
+		return errors.New("no collection set")
+	}
+	names, err := mog.db.ListCollectionNames(mog.ctx, bson.M{"name": mog.collectionName})
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return errors.New("collection does not exist: " + mog.collectionName)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_CreateCollection(t *testing.T) {
+	mog1, disconnect := testMog(t, "log")
+	defer disconnect()
+	mog1.db.Collection("person").Drop(mog1.ctx)
+
+	if err := mog1.CreateCollection("log", CollectionOpts{CappedSize: 4096, CappedMaxDocs: 2}); err != nil {
+		t.Fatal("CreateCollection Capped Failed", err)
+	}
+	for i := 0; i < 3; i++ {
+		mog1.Insert(m{"n": i})
+	}
+	count, _ := mog1.Count(bson.D{})
+	if count != 2 {
+		t.Fatal("Capped Collection Not Limited", count)
+	}
+
+	schema := bson.M{"bsonType": "object", "required": bson.A{"name"}}
+	err := mog1.CreateCollection("person", CollectionOpts{JSONSchema: schema, CollationLocale: "en", CaseInsensitive: true})
+	if err != nil {
+		t.Fatal("CreateCollection Validator Failed", err)
+	}
+	mog1.SetCollection("person")
+	if err = mog1.Insert(m{"nickname": "Bob"}); err == nil {
+		t.Fatal("Validator Not Enforced")
+	}
+	mog1.Insert(m{"name": "Robert"})
+	count, _ = mog1.Count(m{"name": "ROBERT"})
+	if count != 1 {
+		t.Fatal("Default Collation Not Applied", count)
+	}
+	fmt.Println("createCollection successful")
+}
+
+func Test_CollectionAdmin(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.db.Collection("property_renamed").Drop(mog1.ctx)
+
+	if err := mog1.RenameCollection("property_renamed"); err != nil {
+		t.Fatal("RenameCollection Failed", err)
+	}
+	count, _ := mog1.Count(bson.D{})
+	if mog1.collectionName != "property_renamed" || count != 3 {
+		t.Fatal("RenameCollection Did Not Switch Collection", mog1.collectionName, count)
+	}
+	infos, err := mog1.ListCollections()
+	if err != nil {
+		t.Fatal("ListCollections Failed", err)
+	}
+	names := make(map[string]string)
+	for _, info := range infos {
+		names[info.Name] = info.Type
+	}
+	if names["property_renamed"] != "collection" || names["property"] != "" {
+		t.Fatal("ListCollections Wrong Results", names)
+	}
+	if err = mog1.DropCollection(); err != nil {
+		t.Fatal("DropCollection Failed", err)
+	}
+	infos, _ = mog1.ListCollections()
+	for _, info := range infos {
+		if info.Name == "property_renamed" {
+			t.Fatal("DropCollection Did Not Drop")
+		}
+	}
+	fmt.Println("collection admin successful")
+}
+
+func Test_Stats(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	collStats, err := mog1.CollStats()
+	if err != nil || collStats.Count != 3 || collStats.Size == 0 || collStats.NumIndexes != 1 || collStats.IndexSizes["_id_"] == 0 {
+		t.Fatal("CollStats Failed", err, collStats)
+	}
+	dbStats, err := mog1.DBStats()
+	if err != nil || dbStats.Collections == 0 || dbStats.Objects < 3 {
+		t.Fatal("DBStats Failed", err, dbStats)
+	}
+	fmt.Println("stats successful")
+}
+
+func Test_Healthy(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	if err := mog1.Ping(time.Second); err != nil {
+		t.Fatal("Ping Failed", err)
+	}
+	if err := mog1.Healthy(); err == nil {
+		t.Fatal("Healthy Did Not Detect Missing Collection")
+	}
+	testProps(t, mog1)
+	if err := mog1.Healthy(); err != nil {
+		t.Fatal("Healthy Failed", err)
+	}
+	fmt.Println("healthy successful")
+}
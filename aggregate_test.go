@@ -1,9 +1,12 @@
 package mog
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"fmt"
 	"log"
+	"testing"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -166,3 +169,737 @@ func ExampleAggregate() {
 	// Las Vegas 1 13 19.25
 	// Wonder 2 17 20.75
 }
+
+func Test_AggRunOn(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.db.Collection("property_copy").Drop(mog1.ctx)
+	mog1.SetCollection("property_copy")
+	mog1.Insert(Property{Id: "c1", City: "Wonder", St: "MT"})
+	mog1.SetCollection("property")
+
+	mog1.AggStart()
+	mog1.AggTotal("st")
+	mog1.AggSort("_id")
+	type stCount struct {
+		St    string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	var counts1, counts2 []stCount
+	if err := mog1.AggRunOn("property", &counts1); err != nil {
+		t.Fatal("AggRunOn Failed", err)
+	}
+	if err := mog1.AggRunOn("property_copy", &counts2); err != nil {
+		t.Fatal("AggRunOn Failed", err)
+	}
+	if len(counts1) != 2 || counts1[0].Count != 2 || len(counts2) != 1 || counts2[0].Count != 1 {
+		t.Fatal("AggRunOn Wrong Results", counts1, counts2)
+	}
+	if mog1.collectionName != "property" {
+		t.Fatal("AggRunOn Changed Collection", mog1.collectionName)
+	}
+	fmt.Println("aggRunOn successful")
+}
+
+func Test_AggCoalesce(t *testing.T) {
+	mog1, disconnect := testMog(t, "person")
+	defer disconnect()
+	mog1.Insert(
+		bson.M{"_id": "1", "name": "Robert", "nickname": "Bob"},
+		bson.M{"_id": "2", "name": "Alice", "nickname": nil},
+		bson.M{"_id": "3", "name": "Carl"},
+	)
+	mog1.AggStart()
+	mog1.AggCoalesce("display_name", "nickname", "name")
+	mog1.AggSort("_id")
+	var results []struct {
+		DisplayName string `bson:"display_name"`
+	}
+	if err := mog1.AggRunAll(&results); err != nil {
+		t.Fatal("AggCoalesce Failed", err)
+	}
+	if len(results) != 3 || results[0].DisplayName != "Bob" || results[1].DisplayName != "Alice" || results[2].DisplayName != "Carl" {
+		t.Fatal("AggCoalesce Wrong Results", results)
+	}
+	fmt.Println("aggCoalesce successful")
+}
+
+func Test_AggCsvStream(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.AggStart()
+	mog1.AggTotal("st", "sum_fld1")
+	mog1.AggSort("_id")
+	var buf bytes.Buffer
+	if err := mog1.AggCsvStream(&buf, []string{"_id", "count", "tot_sum_fld1"}, true); err != nil {
+		t.Fatal("AggCsvStream Failed", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal("AggCsvStream Parse Failed", err)
+	}
+	want := [][]string{{"_id", "count", "tot_sum_fld1"}, {"MT", "2", "17"}, {"NV", "1", "13"}}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Fatal("AggCsvStream Wrong Results", records)
+	}
+	fmt.Println("aggCsvStream successful")
+}
+
+func Test_AggChildRollup(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.db.Collection("location").Drop(mog1.ctx)
+	mog1.SetCollection("location")
+	mog1.Insert(Location{Id: "7", LocationName: "Northwest"}, Location{Id: "10", LocationName: "Southwest"}, Location{Id: "12", LocationName: "East"})
+
+	mog1.AggStart()
+	mog1.AggChildRollup("property", "location_id", "sum_fld1", "tot_sum_fld1")
+	mog1.AggSort("_id")
+	var results []struct {
+		Id         string `bson:"_id"`
+		TotSumFld1 int    `bson:"tot_sum_fld1"`
+	}
+	if err := mog1.AggRunAll(&results); err != nil {
+		t.Fatal("AggChildRollup Failed", err)
+	}
+	want := map[string]int{"7": 17, "10": 13, "12": 0}
+	if len(results) != 3 {
+		t.Fatal("AggChildRollup Wrong Result Count", results)
+	}
+	for _, result := range results {
+		if want[result.Id] != result.TotSumFld1 {
+			t.Fatal("AggChildRollup Wrong Total", result)
+		}
+	}
+	fmt.Println("aggChildRollup successful")
+}
+
+func Test_AggCreateView(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.db.Collection("property_by_st").Drop(mog1.ctx)
+
+	mog1.AggStart()
+	mog1.AggTotal("st", "sum_fld1")
+	if err := mog1.AggCreateView("property_by_st"); err != nil {
+		t.Fatal("AggCreateView Failed", err)
+	}
+	mog1.SetCollection("property_by_st")
+	var result struct {
+		Count      int `bson:"count"`
+		TotSumFld1 int `bson:"tot_sum_fld1"`
+	}
+	if err := mog1.FindId("MT", &result); err != nil || result.Count != 2 || result.TotSumFld1 != 17 {
+		t.Fatal("Query View Failed", err, result)
+	}
+	fmt.Println("aggCreateView successful")
+}
+
+func Test_AggVectorSearch(t *testing.T) {
+	mog1 := NewMog(context.Background(), nil)
+	mog1.AggStart()
+	mog1.AggVectorSearch("plot_index", "plot_embedding", []float64{0.1, 0.2}, 100, 5)
+	stage, ok := mog1.AggPipeline[0]["$vectorSearch"].(bson.M)
+	if !ok || stage["index"] != "plot_index" || stage["path"] != "plot_embedding" || stage["numCandidates"] != 100 || stage["limit"] != 5 {
+		t.Fatal("AggVectorSearch Failed", mog1.AggPipeline)
+	}
+	fmt.Println("aggVectorSearch successful")
+}
+
+func Test_AggGeoNear(t *testing.T) {
+	mog1, disconnect := testMog(t, "place")
+	defer disconnect()
+	point := func(lng, lat float64) bson.M { return bson.M{"type": "Point", "coordinates": bson.A{lng, lat}} }
+	mog1.Insert(
+		bson.M{"_id": "strip", "location": point(-115.17, 36.11)},
+		bson.M{"_id": "downtown", "location": point(-115.14, 36.17)},
+		bson.M{"_id": "reno", "location": point(-119.81, 39.53)},
+	)
+	if _, err := mog1.CreateIndex(bson.D{{Key: "location", Value: "2dsphere"}}, IndexOpts{}); err != nil {
+		t.Fatal("CreateIndex Failed", err)
+	}
+	mog1.AggStart()
+	mog1.AggSort("_id")
+	if err := mog1.AggGeoNear("location", -115.17, 36.11, "distance", 10000); err == nil {
+		t.Fatal("AggGeoNear Stage Order Not Checked")
+	}
+	mog1.AggStart()
+	if err := mog1.AggGeoNear("location", -115.17, 36.11, "distance", 10000); err != nil {
+		t.Fatal("AggGeoNear Failed", err)
+	}
+	var results []struct {
+		Id       string  `bson:"_id"`
+		Distance float64 `bson:"distance"`
+	}
+	if err := mog1.AggRunAll(&results); err != nil {
+		t.Fatal("AggGeoNear Run Failed", err)
+	}
+	if len(results) != 2 || results[0].Id != "strip" || results[0].Distance != 0 || results[1].Distance < 5000 {
+		t.Fatal("AggGeoNear Wrong Results", results)
+	}
+	fmt.Println("aggGeoNear successful")
+}
+
+func Test_AggMatchLimitSkip(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.AggStart()
+	mog1.AggMatch(bson.M{"st": "MT"})
+	mog1.AggSort("_id")
+	mog1.AggSkip(1)
+	mog1.AggLimit(1)
+	var props []Property
+	if err := mog1.AggRunAll(&props); err != nil {
+		t.Fatal("AggMatch Failed", err)
+	}
+	if len(props) != 1 || props[0].Id != "p2" {
+		t.Fatal("AggMatch/AggSkip/AggLimit Wrong Results", props)
+	}
+	fmt.Println("aggMatchLimitSkip successful")
+}
+
+func Test_AggUnwind(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	mog1.Insert(
+		Property{Id: "p1", Notes: []string{"roof", "pool"}},
+		Property{Id: "p2", Notes: []string{}},
+	)
+
+	mog1.AggStart()
+	mog1.AggUnwind("notes", false)
+	mog1.AggSort("_id")
+	var results []struct {
+		Id    string `bson:"_id"`
+		Notes string `bson:"notes"`
+		Index *int64 `bson:"note_index"`
+	}
+	if err := mog1.AggRunAll(&results); err != nil || len(results) != 2 || results[0].Notes != "roof" {
+		t.Fatal("AggUnwind Failed", err, results)
+	}
+	mog1.AggStart()
+	mog1.AggUnwind("notes", true, "note_index")
+	mog1.AggSort("_id", "note_index")
+	if err := mog1.AggRunAll(&results); err != nil || len(results) != 3 {
+		t.Fatal("AggUnwind Preserve Failed", err, results)
+	}
+	if *results[1].Index != 1 || results[2].Id != "p2" || results[2].Index != nil {
+		t.Fatal("AggUnwind Index Failed", results)
+	}
+	fmt.Println("aggUnwind successful")
+}
+
+func Test_AggFacet(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	byCity := NewMog(mog1.ctx, nil)
+	byCity.AggStart()
+	byCity.AggTotal("city", "sum_fld1")
+	byCity.AggSort("_id")
+	top := NewMog(mog1.ctx, nil)
+	top.AggStart()
+	top.AggSort("-sum_fld1")
+	top.AggLimit(1)
+
+	mog1.AggStart()
+	mog1.AggFacet(map[string][]bson.M{
+		"by_city": byCity.AggPipeline,
+		"top":     top.AggPipeline,
+		"total":   {{"$count": "count"}},
+	})
+	var results []struct {
+		ByCity []struct {
+			City       string `bson:"_id"`
+			Count      int    `bson:"count"`
+			TotSumFld1 int    `bson:"tot_sum_fld1"`
+		} `bson:"by_city"`
+		Top   []Property `bson:"top"`
+		Total []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := mog1.AggRunAll(&results); err != nil || len(results) != 1 {
+		t.Fatal("AggFacet Failed", err, results)
+	}
+	facets := results[0]
+	if len(facets.ByCity) != 2 || facets.ByCity[1].City != "Wonder" || facets.ByCity[1].TotSumFld1 != 17 {
+		t.Fatal("AggFacet By City Failed", facets.ByCity)
+	}
+	if len(facets.Top) != 1 || facets.Top[0].Id != "p3" || facets.Total[0].Count != 3 {
+		t.Fatal("AggFacet Top/Total Failed", facets.Top, facets.Total)
+	}
+	fmt.Println("aggFacet successful")
+}
+
+func Test_AggBucket(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.AggStart()
+	mog1.AggBucket("sum_fld1", []interface{}{0, 10}, "other", bson.M{"count": bson.M{"$sum": 1}, "ids": bson.M{"$push": "$_id"}})
+	var buckets []struct {
+		Id    interface{} `bson:"_id"`
+		Count int         `bson:"count"`
+		Ids   []string    `bson:"ids"`
+	}
+	if err := mog1.AggRunAll(&buckets); err != nil || len(buckets) != 2 {
+		t.Fatal("AggBucket Failed", err, buckets)
+	}
+	if buckets[0].Count != 1 || buckets[0].Ids[0] != "p1" || buckets[1].Id != "other" || buckets[1].Count != 2 {
+		t.Fatal("AggBucket Wrong Results", buckets)
+	}
+
+	mog1.AggStart()
+	mog1.AggBucketAuto("sum_fld1", 3)
+	var autoBuckets []struct {
+		Id struct {
+			Min int `bson:"min"`
+			Max int `bson:"max"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := mog1.AggRunAll(&autoBuckets); err != nil || len(autoBuckets) != 3 || autoBuckets[0].Id.Min != 7 {
+		t.Fatal("AggBucketAuto Failed", err, autoBuckets)
+	}
+	fmt.Println("aggBucket successful")
+}
+
+func Test_AggGraphLookup(t *testing.T) {
+	mog1, disconnect := testMog(t, "employee")
+	defer disconnect()
+	mog1.Insert(
+		bson.M{"_id": "ceo"},
+		bson.M{"_id": "vp", "manager_id": "ceo"},
+		bson.M{"_id": "dir", "manager_id": "vp"},
+		bson.M{"_id": "dev", "manager_id": "dir"},
+	)
+	run := func(maxDepth int) int {
+		mog1.AggStart()
+		mog1.AggMatch(bson.M{"_id": "dev"})
+		mog1.AggGraphLookup("employee", "manager_id", "manager_id", "_id", "managers", maxDepth)
+		var results []struct {
+			Managers []bson.M `bson:"managers"`
+		}
+		if err := mog1.AggRunAll(&results); err != nil || len(results) != 1 {
+			t.Fatal("AggGraphLookup Failed", err, results)
+		}
+		return len(results[0].Managers)
+	}
+	if count := run(-1); count != 3 {
+		t.Fatal("AggGraphLookup Unlimited Failed", count)
+	}
+	if count := run(0); count != 1 {
+		t.Fatal("AggGraphLookup MaxDepth Failed", count)
+	}
+	fmt.Println("aggGraphLookup successful")
+}
+
+func Test_AggAddFields(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.AggStart()
+	mog1.AggAddFields(bson.M{
+		"full_address": Concat("$address", ", ", "$city"),
+		"double":       Multiply("$sum_fld1", 2),
+		"level":        Cond(bson.M{"$gte": bson.A{"$sum_fld1", 10}}, "high", "low"),
+	})
+	mog1.AggSort("_id")
+	var results []struct {
+		FullAddress string `bson:"full_address"`
+		Double      int    `bson:"double"`
+		Level       string `bson:"level"`
+	}
+	if err := mog1.AggRunAll(&results); err != nil || len(results) != 3 {
+		t.Fatal("AggAddFields Failed", err, results)
+	}
+	if results[0].FullAddress != "200 Willow Rd, Wonder" || results[0].Double != 14 || results[0].Level != "low" || results[1].Level != "high" {
+		t.Fatal("AggAddFields Wrong Results", results)
+	}
+	fmt.Println("aggAddFields successful")
+}
+
+func Test_AggReplaceRoot(t *testing.T) {
+	mog1, disconnect := testMog(t, "location")
+	defer disconnect()
+	mog1.Insert(bson.M{"_id": "7", "name": "North"}, bson.M{"_id": "10", "name": "South"})
+	mog1.SetCollection("property")
+	mog1.DropCollection()
+	testProps(t, mog1)
+
+	mog1.AggStart()
+	mog1.AggMatch(bson.M{"_id": "p3"})
+	mog1.AggLookupId("location", "location_id")
+	mog1.AggReplaceRoot("location")
+	var locations []bson.M
+	if err := mog1.AggRunAll(&locations); err != nil || len(locations) != 1 || locations[0]["name"] != "South" {
+		t.Fatal("AggReplaceRoot Failed", err, locations)
+	}
+	mog1.AggStart()
+	mog1.AggMatch(bson.M{"_id": "p3"})
+	mog1.AggLookupId("location", "location_id")
+	mog1.AggReplaceRoot(bson.M{"$mergeObjects": bson.A{"$location", bson.M{"prop_id": "$_id"}}})
+	if err := mog1.AggRunAll(&locations); err != nil || len(locations) != 1 || locations[0]["prop_id"] != "p3" {
+		t.Fatal("AggReplaceRoot Expression Failed", err, locations)
+	}
+	fmt.Println("aggReplaceRoot successful")
+}
+
+func Test_AggRunCount(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.AggStart()
+	mog1.AggMatch(bson.M{"st": "MT"})
+	if count, err := mog1.AggRunCount(); err != nil || count != 2 || len(mog1.AggPipeline) != 1 {
+		t.Fatal("AggRunCount Failed", err, count, mog1.AggPipeline)
+	}
+	mog1.AggCountStage("total")
+	if count, err := mog1.AggRunCount(); err != nil || count != 2 {
+		t.Fatal("AggRunCount With AggCountStage Failed", err, count)
+	}
+	mog1.AggStart()
+	mog1.AggMatch(bson.M{"st": "XX"})
+	if count, err := mog1.AggRunCount(); err != nil || count != 0 {
+		t.Fatal("AggRunCount No Docs Failed", err, count)
+	}
+	fmt.Println("aggRunCount successful")
+}
+
+func Test_AggOut(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.db.Collection("property_summary").Drop(mog1.ctx)
+
+	mog1.AggStart()
+	mog1.AggTotal("st", "sum_fld1")
+	mog1.AggOut("property_summary")
+	if err := mog1.AggRun(); err != nil {
+		t.Fatal("AggOut Failed", err)
+	}
+	var doc bson.M
+	if mog1.Next(&doc) || mog1.IterErr() != nil {
+		t.Fatal("AggOut Next Returned Results", doc, mog1.IterErr())
+	}
+	mog1.SetCollection("property_summary")
+	if count, err := mog1.Count(bson.M{}); err != nil || count != 2 {
+		t.Fatal("AggOut Wrong Output", err, count)
+	}
+	fmt.Println("aggOut successful")
+}
+
+func Test_AggMerge(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.db.Collection("property_summary").Drop(mog1.ctx)
+
+	runMerge := func() {
+		mog1.AggStart()
+		mog1.AggTotal("st", "sum_fld1")
+		mog1.AggMerge("property_summary", nil, "replace", "insert")
+		if err := mog1.AggRun(); err != nil {
+			t.Fatal("AggMerge Failed", err)
+		}
+	}
+	runMerge()
+	mog1.Insert(Property{Id: "p4", St: "NV", SumFld1: 5})
+	runMerge()
+
+	var summary []struct {
+		St    string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	mog1.SetCollection("property_summary")
+	if err := mog1.FindAll(nil, &summary); err != nil || len(summary) != 2 {
+		t.Fatal("AggMerge Wrong Output", err, summary)
+	}
+	for _, st := range summary {
+		if st.Count != 2 {
+			t.Fatal("AggMerge Not Updated", summary)
+		}
+	}
+	fmt.Println("aggMerge successful")
+}
+
+func Test_AggWindow(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.AggStart()
+	mog1.AggWindow("st", "date_added", bson.M{
+		"running_tot": RunningTotal("sum_fld1"),
+		"rank":        Rank(),
+		"moving_avg":  MovingAvg("sum_fld1", 2),
+	})
+	mog1.AggSort("_id")
+	var results []struct {
+		Id         string  `bson:"_id"`
+		RunningTot int     `bson:"running_tot"`
+		Rank       int     `bson:"rank"`
+		MovingAvg  float64 `bson:"moving_avg"`
+	}
+	if err := mog1.AggRunAll(&results); err != nil || len(results) != 3 {
+		t.Fatal("AggWindow Failed", err, results)
+	}
+	p2, p3 := results[1], results[2]
+	if p2.RunningTot != 17 || p2.Rank != 2 || p2.MovingAvg != 8.5 || p3.RunningTot != 13 || p3.Rank != 1 {
+		t.Fatal("AggWindow Wrong Results", results)
+	}
+
+	mog1.AggStart() // no partition or order, whole collection is the window
+	mog1.AggWindow("", "", bson.M{"running_tot": bson.M{"$sum": "$sum_fld1"}})
+	if err := mog1.AggRunAll(&results); err != nil || len(results) != 3 || results[0].RunningTot != results[2].RunningTot {
+		t.Fatal("AggWindow No Sort Failed", err, results)
+	}
+	fmt.Println("aggWindow successful")
+}
+
+func Test_AggDensifyFill(t *testing.T) {
+	mog1, disconnect := testMog(t, "reading")
+	defer disconnect()
+	day := func(d int) time.Time { return time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC) }
+	mog1.Insert(
+		bson.M{"taken": day(1), "temp": 10.0, "source": "sensor"},
+		bson.M{"taken": day(4), "temp": 40.0, "source": "sensor"},
+	)
+
+	mog1.AggStart()
+	mog1.AggDensify("taken", "day", 1)
+	mog1.AggFill(bson.M{"temp": bson.M{"method": "linear"}, "source": bson.M{"value": "filled"}}, "taken")
+	mog1.AggSort("taken")
+	var readings []struct {
+		Temp   float64 `bson:"temp"`
+		Source string  `bson:"source"`
+	}
+	if err := mog1.AggRunAll(&readings); err != nil || len(readings) != 4 {
+		t.Fatal("AggDensify Failed", err, readings)
+	}
+	if readings[1].Temp != 20 || readings[2].Source != "filled" || readings[3].Source != "sensor" {
+		t.Fatal("AggFill Wrong Results", readings)
+	}
+	fmt.Println("aggDensifyFill successful")
+}
+
+func Test_AggLookupPipeline(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.db.Collection("location").Drop(mog1.ctx)
+	mog1.SetCollection("location")
+	mog1.Insert(Location{Id: "7", LocationName: "Northwest"}, Location{Id: "10", LocationName: "Southwest"})
+
+	mog1.AggStart()
+	mog1.AggLookupPipeline("property", bson.M{"loc": "$_id"}, []bson.M{
+		{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$location_id", "$$loc"}}, "sum_fld1": bson.M{"$gte": 10}}},
+		{"$project": bson.M{"city": 1}},
+	}, "properties")
+	mog1.AggSort("_id")
+	var results []struct {
+		Id         string     `bson:"_id"`
+		Properties []Property `bson:"properties"`
+	}
+	if err := mog1.AggRunAll(&results); err != nil || len(results) != 2 {
+		t.Fatal("AggLookupPipeline Failed", err, results)
+	}
+	if len(results[0].Properties) != 1 || results[0].Properties[0].Id != "p3" || len(results[1].Properties) != 1 || results[1].Properties[0].Id != "p2" {
+		t.Fatal("AggLookupPipeline Wrong Results", results)
+	}
+	if results[1].Properties[0].City != "Wonder" || results[1].Properties[0].St != "" {
+		t.Fatal("AggLookupPipeline Projection Failed", results[1].Properties)
+	}
+	fmt.Println("aggLookupPipeline successful")
+}
+
+func Test_AggLookupMany(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.db.Collection("location").Drop(mog1.ctx)
+	mog1.SetCollection("location")
+	mog1.Insert(Location{Id: "7", LocationName: "Northwest"}, Location{Id: "12", LocationName: "East"})
+
+	mog1.AggStart()
+	mog1.AggLookupMany("property", "_id", "location_id", "properties")
+	mog1.AggSort("_id")
+	var results []struct {
+		Id         string     `bson:"_id"`
+		Properties []Property `bson:"properties"`
+	}
+	if err := mog1.AggRunAll(&results); err != nil || len(results) != 2 {
+		t.Fatal("AggLookupMany Failed", err, results)
+	}
+	if len(results[0].Properties) != 0 || len(results[1].Properties) != 2 {
+		t.Fatal("AggLookupMany Wrong Results", results)
+	}
+	fmt.Println("aggLookupMany successful")
+}
+
+func Test_AggGroupByDate(t *testing.T) {
+	mog1, disconnect := testMog(t, "sale")
+	defer disconnect()
+	mog1.Insert(
+		bson.M{"sold_at": time.Date(2023, 1, 5, 12, 0, 0, 0, time.UTC), "price": 100},
+		bson.M{"sold_at": time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC), "price": 50},
+		bson.M{"sold_at": time.Date(2023, 2, 1, 3, 0, 0, 0, time.UTC), "price": 25}, // Jan 31 in Denver
+	)
+	type period struct {
+		Start time.Time `bson:"_id"`
+		Count int       `bson:"count"`
+		Total int       `bson:"total"`
+	}
+	var periods []period
+	mog1.AggStart()
+	mog1.AggGroupByDate("sold_at", "month", bson.M{"count": bson.M{"$sum": 1}, "total": bson.M{"$sum": "$price"}})
+	if err := mog1.AggRunAll(&periods); err != nil || len(periods) != 2 {
+		t.Fatal("AggGroupByDate Failed", err, periods)
+	}
+	if !periods[0].Start.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)) || periods[0].Total != 150 || periods[1].Count != 1 {
+		t.Fatal("AggGroupByDate Wrong Results", periods)
+	}
+	mog1.AggStart()
+	mog1.AggGroupByDate("sold_at", "month", nil, "America/Denver")
+	if err := mog1.AggRunAll(&periods); err != nil || len(periods) != 1 || periods[0].Count != 3 {
+		t.Fatal("AggGroupByDate Timezone Failed", err, periods)
+	}
+	fmt.Println("aggGroupByDate successful")
+}
+
+func Test_AggTopNPerGroup(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.Insert(Property{Id: "p4", City: "Wonder", St: "MT", DateAdded: "2020-01-01"})
+
+	mog1.AggStart()
+	if err := mog1.AggTopNPerGroup("city", "date_added", 2); err != nil {
+		t.Fatal("AggTopNPerGroup Failed", err)
+	}
+	var props []Property
+	if err := mog1.AggRunAll(&props); err != nil || len(props) != 3 {
+		t.Fatal("AggTopNPerGroup Run Failed", err, props)
+	}
+	if props[0].Id != "p3" || props[1].Id != "p4" || props[2].Id != "p2" {
+		t.Fatal("AggTopNPerGroup Wrong Results", props)
+	}
+	if err := NewMog(context.Background(), nil).AggTopNPerGroup("city", "date_added", 2); err == nil {
+		t.Fatal("AggTopNPerGroup No Database Not Rejected")
+	}
+	fmt.Println("aggTopNPerGroup successful")
+}
+
+func Test_AggPivot(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.Insert(Property{Id: "p4", City: "Wonder", St: "NV", SumFld1: 4})
+	mog1.Insert(m{"_id": "p5", "city": "Wonder", "sum_fld1": 1}) // no st
+
+	mog1.AggStart()
+	mog1.AggPivot("city", "st", "sum_fld1", "sum")
+	var rows []bson.M
+	if err := mog1.AggRunAll(&rows); err != nil || len(rows) != 2 {
+		t.Fatal("AggPivot Failed", err, rows)
+	}
+	wonder := rows[1]
+	if wonder["_id"] != "Wonder" || wonder["MT"] != int32(17) || wonder["NV"] != int32(4) || wonder["null"] != int32(1) || rows[0]["MT"] != nil {
+		t.Fatal("AggPivot Wrong Results", rows)
+	}
+	mog1.AggStart()
+	mog1.AggPivot("st", "city", "", "count")
+	if err := mog1.AggRunAll(&rows); err != nil || len(rows) != 3 || rows[0]["_id"] != nil || rows[1]["Wonder"] != int32(2) {
+		t.Fatal("AggPivot Count Failed", err, rows)
+	}
+	fmt.Println("aggPivot successful")
+}
+
+func Test_AggPipelineJSON(t *testing.T) {
+	mog1 := NewMog(context.Background(), nil)
+	mog1.AggStart()
+	mog1.AggMatch(bson.M{"date_added": bson.M{"$gte": time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}})
+	mog1.AggSort("st", "-city")
+	pipelineJSON, err := mog1.AggPipelineJSON()
+	if err != nil {
+		t.Fatal("AggPipelineJSON Failed", err)
+	}
+	want := `[
+  {"$match":{"date_added":{"$gte":{"$date":"2019-01-01T00:00:00Z"}}}},
+  {"$sort":{"st":1,"city":-1}}
+]`
+	if pipelineJSON != want {
+		t.Fatal("AggPipelineJSON Wrong Result", pipelineJSON)
+	}
+	if err = mog1.AggLoadPipeline(`[{"$match": {"st": "NV"}}, {"$limit": {"$numberInt": "5"}}]`); err != nil {
+		t.Fatal("AggLoadPipeline Failed", err)
+	}
+	if len(mog1.AggPipeline) != 2 || mog1.AggPipeline[0]["$match"].(bson.D)[0].Value != "NV" || mog1.AggPipeline[1]["$limit"] != int32(5) {
+		t.Fatal("AggLoadPipeline Wrong Result", mog1.AggPipeline)
+	}
+	if err = mog1.AggLoadPipeline(`[{"$match": `); err == nil {
+		t.Fatal("AggLoadPipeline Invalid JSON Not Rejected")
+	}
+	if err = mog1.AggLoadPipeline(`[{"$match": {}}, {"$match": {}, "$limit": 1}]`); err == nil || len(mog1.AggPipeline) != 2 {
+		t.Fatal("AggLoadPipeline Invalid Stage Changed Pipeline", err, mog1.AggPipeline)
+	}
+	fmt.Println("aggPipelineJSON successful")
+}
+
+func Test_AggStageD(t *testing.T) {
+	mog1 := NewMog(context.Background(), nil)
+	mog1.AggStart()
+	mog1.AggStageD("sort", bson.D{{Key: "st", Value: 1}, {Key: "city", Value: -1}, {Key: "address", Value: 1}})
+	if err := mog1.AggAddStage(bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$st"}, {Key: "first", Value: bson.M{"$first": "$city"}}}}}); err != nil {
+		t.Fatal("AggAddStage Failed", err)
+	}
+	if err := mog1.AggAddStage(bson.D{{Key: "$match", Value: bson.M{}}, {Key: "$limit", Value: 1}}); err == nil {
+		t.Fatal("AggAddStage Multiple Keys Not Rejected")
+	}
+	if err := mog1.AggLoadPipeline(`[{"$sort": {"st": 1, "city": -1, "address": 1}}]`); err != nil {
+		t.Fatal("AggLoadPipeline Failed", err)
+	}
+	pipelineJSON, _ := mog1.AggPipelineJSON()
+	if pipelineJSON != "[\n  {\"$sort\":{\"st\":1,\"city\":-1,\"address\":1}}\n]" {
+		t.Fatal("AggLoadPipeline Order Not Kept", pipelineJSON)
+	}
+	fmt.Println("aggStageD successful")
+}
+
+func Test_AggAllowDiskUse(t *testing.T) {
+	mog1 := NewMog(context.Background(), nil)
+	mog1.AggAllowDiskUse()
+	aggOptions := mog1.aggOptions()
+	if aggOptions.AllowDiskUse == nil || !*aggOptions.AllowDiskUse {
+		t.Fatal("AggAllowDiskUse Failed", aggOptions.AllowDiskUse)
+	}
+	if aggOptions = mog1.aggOptions(); aggOptions.AllowDiskUse != nil {
+		t.Fatal("AggAllowDiskUse Not Reset", *aggOptions.AllowDiskUse)
+	}
+	fmt.Println("aggAllowDiskUse successful")
+}
+
+func Test_AggPipelineString(t *testing.T) {
+	mog1 := NewMog(context.Background(), nil)
+	mog1.AggStart()
+	mog1.AggMatch(bson.M{"st": "NV"})
+	mog1.AggLimit(5)
+	want := "{\n  \"$match\": {\n    \"st\": \"NV\"\n  }\n}\n{\n  \"$limit\": 5\n}\n"
+	if got := mog1.AggPipelineString(); got != want {
+		t.Fatal("AggPipelineString Wrong Result", got)
+	}
+	fmt.Println("aggPipelineString successful")
+}
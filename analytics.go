@@ -0,0 +1,226 @@
+package mog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// --- Analytics Methods ----------------------------------------------------
+
+// ValueCount is a distinct field value and the number of docs having it, see DistinctWithCounts.
+type ValueCount struct {
+	Value interface{} `bson:"_id"`
+	Count int64       `bson:"count"`
+}
+
+// DistinctWithCounts returns the distinct values of field in docs matching criteria (nil for all docs),
+// with the number of docs having each value. Results are sorted by count descending, then value.
+func (mog *Mog) DistinctWithCounts(field string, criteria interface{}) ([]ValueCount, error) {
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	criteria = mog.notDeleted(criteria)
+	pipeline := []bson.M{
+		{"$match": criteria},
+		{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+		{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+	var results []ValueCount
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			cursor, err := mog.collection.Aggregate(mog.ctx, pipeline)
+			if err != nil {
+				return err
+			}
+			return cursor.All(mog.ctx, &results)
+		})
+	})
+	return results, err
+}
+
+// FindLatestPerGroup loads docs with the doc having the highest sortField value for each value of groupField.
+// Parm "docs" should be pointer to slice. Results are sorted by groupField.
+// Ex: FindLatestPerGroup("sensor_id", "read_at", &readings) returns the latest reading for each sensor.
+func (mog *Mog) FindLatestPerGroup(groupField, sortField string, docs interface{}) error {
+	pipeline := []bson.M{
+		{"$sort": bson.M{sortField: -1}},
+		{"$group": bson.M{"_id": "$" + groupField, "doc": bson.M{"$first": "$$ROOT"}}},
+		{"$replaceRoot": bson.M{"newRoot": "$doc"}},
+		{"$sort": bson.M{groupField: 1}},
+	}
+	pipeline = mog.notDeletedPipeline(pipeline)
+	opts := options.Aggregate().SetAllowDiskUse(true)
+	return mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			cursor, err := mog.collection.Aggregate(mog.ctx, pipeline, opts)
+			if err != nil {
+				return err
+			}
+			return cursor.All(mog.ctx, docs)
+		})
+	})
+}
+
+// Percentiles returns the values of numeric field at each percentile in ps, for docs matching criteria.
+// Percentiles are expressed as fractions, 0.5 is the median, 0.95 is p95.
+// Result map is keyed by the values in ps.
+// Servers 7.0+ use the $percentile accumulator, older servers sort the values and pick by rank.
+func (mog *Mog) Percentiles(field string, ps []float64, criteria interface{}) (map[float64]float64, error) {
+	if len(ps) == 0 {
+		return nil, errors.New("no percentiles requested")
+	}
+	for _, p := range ps {
+		if p < 0 || p > 1 {
+			return nil, errors.New("percentiles must be between 0 and 1")
+		}
+	}
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	criteria = mog.notDeleted(criteria)
+	major, err := mog.serverMajorVersion()
+	if err != nil {
+		return nil, err
+	}
+	if major >= 7 {
+		return mog.percentilesAccumulator(field, ps, criteria)
+	}
+	return mog.percentilesSorted(field, ps, criteria)
+}
+
+// Checksum returns the hex SHA-256 digest of all docs matching criteria (nil for all docs).
+// Docs are read in sortFlds order with _id as the final sort key, so the order is deterministic.
+// Two collections containing identical docs return the same checksum.
+// Projection set by Keep/Omit is applied, allowing volatile fields to be excluded.
+func (mog *Mog) Checksum(criteria interface{}, sortFlds ...string) (string, error) {
+	hasId := false
+	for _, fld := range sortFlds {
+		if fld == "_id" || fld == "-_id" {
+			hasId = true
+		}
+	}
+	if !hasId {
+		sortFlds = append(sortFlds, "_id")
+	}
+	findOptions := options.Find().SetSort(CreateSortOrder(sortFlds))
+	if mog.projectFlds != nil {
+		findOptions.SetProjection(mog.projectFlds)
+	}
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	criteria = mog.notDeleted(criteria)
+	hash := sha256.New()
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			cursor, err := mog.collection.Find(mog.ctx, criteria, findOptions)
+			if err != nil {
+				return err
+			}
+			defer cursor.Close(mog.ctx)
+			for cursor.Next(mog.ctx) {
+				hash.Write(cursor.Current)
+			}
+			return cursor.Err()
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// percentilesAccumulator computes percentiles using the $percentile accumulator (server 7.0+).
+func (mog *Mog) percentilesAccumulator(field string, ps []float64, criteria interface{}) (map[float64]float64, error) {
+	pipeline := []bson.M{
+		{"$match": criteria},
+		{"$group": bson.M{
+			"_id": nil,
+			"p":   bson.M{"$percentile": bson.M{"input": "$" + field, "p": ps, "method": "approximate"}},
+		}},
+	}
+	var results []struct {
+		P []float64 `bson:"p"`
+	}
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			cursor, err := mog.collection.Aggregate(mog.ctx, pipeline)
+			if err != nil {
+				return err
+			}
+			return cursor.All(mog.ctx, &results)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 || len(results[0].P) != len(ps) {
+		return nil, errors.New("no values found for field " + field)
+	}
+	out := make(map[float64]float64, len(ps))
+	for i, p := range ps {
+		out[p] = results[0].P[i]
+	}
+	return out, nil
+}
+
+// percentilesSorted computes percentiles by sorting all values and choosing by nearest rank (pre 7.0 servers).
+func (mog *Mog) percentilesSorted(field string, ps []float64, criteria interface{}) (map[float64]float64, error) {
+	pipeline := []bson.M{
+		{"$match": criteria},
+		{"$match": bson.M{field: bson.M{"$type": "number"}}},
+		{"$sort": bson.M{field: 1}},
+		{"$project": bson.M{"_id": 0, "v": bson.M{"$toDouble": "$" + field}}},
+	}
+	var values []struct {
+		V float64 `bson:"v"`
+	}
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			cursor, err := mog.collection.Aggregate(mog.ctx, pipeline)
+			if err != nil {
+				return err
+			}
+			return cursor.All(mog.ctx, &values)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no values found for field " + field)
+	}
+	out := make(map[float64]float64, len(ps))
+	for _, p := range ps {
+		rank := int(math.Ceil(p*float64(len(values)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		out[p] = values[rank].V
+	}
+	return out, nil
+}
+
+// serverMajorVersion returns the major version number of the connected server.
+// Returns error if mog has no database (created using NewMog(ctx, nil) for building pipelines).
+func (mog *Mog) serverMajorVersion() (int, error) {
+	if mog.db == nil {
+		return 0, errors.New("server version not available, mog has no database")
+	}
+	var info struct {
+		VersionArray []int `bson:"versionArray"`
+	}
+	err := mog.db.RunCommand(mog.ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info)
+	if err != nil {
+		return 0, err
+	}
+	if len(info.VersionArray) == 0 {
+		return 0, errors.New("server version not available")
+	}
+	return info.VersionArray[0], nil
+}
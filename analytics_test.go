@@ -0,0 +1,82 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_Percentiles(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	result, err := mog1.Percentiles("sum_fld2", []float64{0.5, 0.95}, nil)
+	if err != nil {
+		t.Fatal("Percentiles Failed", err)
+	}
+	median := result[0.5]
+	if median < 8.25 || median > 19.25 {
+		t.Fatal("Percentiles Median Out Of Range", median)
+	}
+	fmt.Println("percentiles successful", result)
+}
+
+func Test_Checksum(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.db.Collection("property_copy").Drop(mog1.ctx)
+	mog1.AggStart()
+	mog1.AggPipeline = append(mog1.AggPipeline, bson.M{"$out": "property_copy"})
+	if err := mog1.AggRun(); err != nil {
+		t.Fatal("Copy Collection Failed", err)
+	}
+	sum1, err := mog1.Checksum(nil)
+	if err != nil {
+		t.Fatal("Checksum Failed", err)
+	}
+	mog1.SetCollection("property_copy")
+	sum2, _ := mog1.Checksum(nil)
+	if sum1 != sum2 {
+		t.Fatal("Checksum Of Copy Does Not Match", sum1, sum2)
+	}
+	mog1.UpdateId("p2", m{"$set": m{"city": "Elsewhere"}})
+	sum3, _ := mog1.Checksum(nil)
+	if sum3 == sum1 {
+		t.Fatal("Checksum Did Not Change After Update")
+	}
+	fmt.Println("checksum successful")
+}
+
+func Test_DistinctWithCounts(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	results, err := mog1.DistinctWithCounts("st", nil)
+	if err != nil {
+		t.Fatal("DistinctWithCounts Failed", err)
+	}
+	if len(results) != 2 || results[0].Value != "MT" || results[0].Count != 2 || results[1].Value != "NV" || results[1].Count != 1 {
+		t.Fatal("DistinctWithCounts Wrong Results", results)
+	}
+	fmt.Println("distinctWithCounts successful")
+}
+
+func Test_FindLatestPerGroup(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	var props []Property
+	if err := mog1.FindLatestPerGroup("st", "date_added", &props); err != nil {
+		t.Fatal("FindLatestPerGroup Failed", err)
+	}
+	if len(props) != 2 || props[0].Id != "p2" || props[1].Id != "p3" {
+		t.Fatal("FindLatestPerGroup Wrong Results", props)
+	}
+	fmt.Println("findLatestPerGroup successful")
+}
@@ -0,0 +1,179 @@
+package mog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// --- Audit Methods ----------------------------------------------------
+
+// AuditOpts configures the audit trail, see EnableAudit.
+type AuditOpts struct {
+	CollectionName  string                           // collection receiving audit entries, default is <collection>_audit
+	Actor           string                           // who made the changes, such as a user or job name
+	ActorFunc       func(ctx context.Context) string // if not nil, used instead of Actor (e.g. user id stored in request context)
+	SkipBeforeImage bool                             // if true, docs are not read before Update, Replace, Save and Delete..
+}
+
+// AuditEntry is the doc written to the audit collection for each audited write.
+type AuditEntry struct {
+	Collection string      `bson:"collection"`
+	Op         string      `bson:"op"` // insert, update, replace, save, delete, restore
+	Criteria   interface{} `bson:"criteria,omitempty"`
+	Change     interface{} `bson:"change,omitempty"` // inserted doc, update doc or new doc
+	Before     []bson.Raw  `bson:"before,omitempty"` // docs matching criteria before the write
+	Actor      string      `bson:"actor,omitempty"`
+	Time       time.Time   `bson:"time"`
+}
+
+// EnableAudit turns on the audit trail. Each write changing docs is recorded as an AuditEntry: Insert (1 entry per doc),
+// Update, UpdateId, Replace, Save, versioned writes, Delete.., Deduplicate, Restore and FindOneAnd.. methods.
+// Before Update.., Replace, Save, Delete.. and Restore the docs matching criteria are read, for the entry's before-image.
+// FindOneAnd.. entries use the doc returned as before-image (none if ReturnAfter is used).
+// Bulk writes (BulkWrite, auto flushes, BulkFromChannel, BulkBatch, BulkWriter) record 1 entry per written model,
+// without before-images. Pass nil to turn off.
+// If the write succeeds but the audit entry cannot be written, the audit error is returned.
+// Audit entries are written with the write concern set on mog.
+// Ex: mog.EnableAudit(&AuditOpts{Actor: "nightly-import"})
+func (mog *Mog) EnableAudit(opts *AuditOpts) {
+	if opts == nil {
+		mog.auditOpts = nil
+		return
+	}
+	auditOpts := *opts
+	mog.auditOpts = &auditOpts
+}
+
+// auditBefore returns the docs matching criteria (only the 1st if not many), if auditing with before-images.
+func (mog *Mog) auditBefore(criteria interface{}, many bool) ([]bson.Raw, error) {
+	if mog.auditOpts == nil || mog.auditOpts.SkipBeforeImage || mog.dryRun {
+		return nil, nil
+	}
+	findOptions := options.Find()
+	if !many {
+		findOptions.SetLimit(1)
+	}
+	var before []bson.Raw
+	err := mog.retry(func() error {
+		cursor, err := mog.collection.Find(mog.ctx, criteria, findOptions)
+		if err != nil {
+			return err
+		}
+		return cursor.All(mog.ctx, &before)
+	})
+	return before, err
+}
+
+// auditLog writes an AuditEntry for each change (1 with no change if changes is empty), if auditing.
+func (mog *Mog) auditLog(op string, criteria interface{}, changes []interface{}, before []bson.Raw) error {
+	if mog.auditOpts == nil {
+		return nil
+	}
+	if len(changes) == 0 {
+		changes = []interface{}{nil}
+	}
+	entries := make([]AuditEntry, len(changes))
+	for i, change := range changes {
+		entries[i] = AuditEntry{Op: op, Criteria: criteria, Change: change, Before: before}
+	}
+	return mog.auditWrite(op, entries)
+}
+
+// auditFound writes an AuditEntry for a FindOneAnd.. op, if auditing. Parm "found" is the doc returned by the op,
+// used as before-image unless it is the doc after modification (ReturnAfter).
+func (mog *Mog) auditFound(op string, criteria, change interface{}, found bson.Raw, returnAfter bool) error {
+	if mog.auditOpts == nil {
+		return nil
+	}
+	var before []bson.Raw
+	if found != nil && !returnAfter && !mog.auditOpts.SkipBeforeImage {
+		before = []bson.Raw{found}
+	}
+	return mog.auditLog(op, criteria, []interface{}{change}, before)
+}
+
+// auditModels writes an AuditEntry for each bulk write model (no before-images), if auditing.
+func (mog *Mog) auditModels(models []mongo.WriteModel) error {
+	if mog.auditOpts == nil || len(models) == 0 {
+		return nil
+	}
+	entries := make([]AuditEntry, 0, len(models))
+	for _, model := range models {
+		switch m := model.(type) {
+		case *mongo.InsertOneModel:
+			entries = append(entries, AuditEntry{Op: "insert", Change: m.Document})
+		case *mongo.UpdateManyModel:
+			entries = append(entries, AuditEntry{Op: "update", Criteria: m.Filter, Change: m.Update})
+		case *mongo.UpdateOneModel:
+			entries = append(entries, AuditEntry{Op: "update", Criteria: m.Filter, Change: m.Update})
+		case *mongo.ReplaceOneModel:
+			entries = append(entries, AuditEntry{Op: "replace", Criteria: m.Filter, Change: m.Replacement})
+		case *mongo.DeleteManyModel:
+			entries = append(entries, AuditEntry{Op: "delete", Criteria: m.Filter})
+		case *mongo.DeleteOneModel:
+			entries = append(entries, AuditEntry{Op: "delete", Criteria: m.Filter})
+		}
+	}
+	return mog.auditWrite("bulk write", entries)
+}
+
+// writtenModels returns the models of a bulk write that were written, using the error returned by the write.
+// Models having a write error are omitted, for ordered writes so are the models following the 1st failed one.
+func writtenModels(models []mongo.WriteModel, err error, ordered bool) []mongo.WriteModel {
+	if err == nil {
+		return models
+	}
+	var bulkException mongo.BulkWriteException
+	if !errors.As(err, &bulkException) {
+		return nil
+	}
+	failed := make(map[int]bool)
+	for _, writeErr := range bulkException.WriteErrors {
+		failed[writeErr.Index] = true
+	}
+	written := make([]mongo.WriteModel, 0, len(models))
+	for i, model := range models {
+		if failed[i] {
+			if ordered {
+				break
+			}
+			continue
+		}
+		written = append(written, model)
+	}
+	return written
+}
+
+// auditWrite sets the collection, actor and time of entries and writes them to the audit collection.
+func (mog *Mog) auditWrite(op string, entries []AuditEntry) error {
+	actor := mog.auditOpts.Actor
+	if mog.auditOpts.ActorFunc != nil {
+		actor = mog.auditOpts.ActorFunc(mog.ctx)
+	}
+	collectionName := mog.auditOpts.CollectionName
+	if collectionName == "" {
+		collectionName = mog.collectionName + "_audit"
+	}
+	now := time.Now()
+	docs := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		entry.Collection = mog.collectionName
+		entry.Actor = actor
+		entry.Time = now
+		docs[i] = entry
+	}
+	err := mog.retryWrite(func() error {
+		_, err := mog.db.Collection(collectionName, mog.collectionOptions()).InsertMany(mog.ctx, docs)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s done, audit entry not written: %w", op, err)
+	}
+	return nil
+}
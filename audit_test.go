@@ -0,0 +1,62 @@
+package mog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func Test_Audit(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	mog1.db.Collection("property_audit").Drop(mog1.ctx)
+
+	type actorKey struct{}
+	mog1 = mog1.WithContext(context.WithValue(mog1.ctx, actorKey{}, "jdoe"))
+	mog1.EnableAudit(&AuditOpts{ActorFunc: func(ctx context.Context) string { return ctx.Value(actorKey{}).(string) }})
+	testProps(t, mog1)
+	if _, err := mog1.Update(m{"city": "Wonder"}, m{"$inc": m{"sum_fld1": 1}}); err != nil {
+		t.Fatal("Audited Update Failed", err)
+	}
+	if _, err := mog1.Update(m{"city": "Nowhere"}, m{"$inc": m{"sum_fld1": 1}}); err != nil {
+		t.Fatal("Audited Update Of No Docs Failed", err)
+	}
+	if err := mog1.DeleteId("p3"); err != nil {
+		t.Fatal("Audited Delete Failed", err)
+	}
+	var prop Property
+	if err := mog1.FindOneAndUpdate(m{"_id": "p2"}, m{"$set": m{"st": "ID"}}, &prop); err != nil {
+		t.Fatal("Audited FindOneAndUpdate Failed", err)
+	}
+	mog1.BulkStart(1)
+	mog1.BulkAddInsert(Property{Id: "p9"})
+	if _, err := mog1.BulkWrite(); err != nil {
+		t.Fatal("Audited BulkWrite Failed", err)
+	}
+	mog1.EnableAudit(nil)
+	mog1.DeleteId("p1")
+
+	var entries []AuditEntry
+	mog1.SetCollection("property_audit")
+	if err := mog1.FindAll(nil, &entries, "_id"); err != nil {
+		t.Fatal("FindAll Audit Entries Failed", err)
+	}
+	if len(entries) != 7 { // 3 inserts, 1 update, 1 delete, 1 FindOneAndUpdate, 1 bulk insert
+		t.Fatal("Wrong Audit Entry Count", len(entries), entries)
+	}
+	update, deleted := entries[3], entries[4]
+	if update.Op != "update" || len(update.Before) != 2 || update.Actor != "jdoe" || update.Collection != "property" {
+		t.Fatal("Update Audit Entry Wrong", update)
+	}
+	if deleted.Op != "delete" || len(deleted.Before) != 1 || deleted.Before[0].Lookup("_id").StringValue() != "p3" {
+		t.Fatal("Delete Audit Entry Wrong", deleted)
+	}
+	found, bulk := entries[5], entries[6]
+	if found.Op != "update" || len(found.Before) != 1 || found.Before[0].Lookup("st").StringValue() != "MT" {
+		t.Fatal("FindOneAndUpdate Audit Entry Wrong", found)
+	}
+	if bulk.Op != "insert" || bulk.Change == nil {
+		t.Fatal("BulkWrite Audit Entry Wrong", bulk)
+	}
+	fmt.Println("audit successful")
+}
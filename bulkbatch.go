@@ -0,0 +1,106 @@
+package mog
+
+import (
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BulkBatch accumulates bulk write models independently of Mog's Bulk.. methods.
+// Add methods are safe for concurrent use, allowing multiple goroutines to feed one batch.
+// Create using mog.NewBulkBatch.
+type BulkBatch struct {
+	mog    *Mog // clone of the creating mog, for its collection, write rate limit, circuit breaker, dry run, timestamps and audit
+	mu     sync.Mutex
+	models []mongo.WriteModel
+	err    error // 1st BeforeInsert failure, returned by Commit
+}
+
+// NewBulkBatch creates a BulkBatch for mog's current collection, size is estimated # of inserts + updates.
+// Later changes to mog (such as SetCollection) do not affect the batch.
+func (mog *Mog) NewBulkBatch(size int) *BulkBatch {
+	return &BulkBatch{
+		mog:    mog.Clone(),
+		models: make([]mongo.WriteModel, 0, size),
+	}
+}
+
+// AddInsert adds doc to be inserted to the batch.
+// If BeforeInsert of doc returns an error, doc is not added and Commit returns the error without writing.
+func (batch *BulkBatch) AddInsert(doc interface{}) {
+	if err := beforeInsert([]interface{}{doc}); err != nil {
+		batch.mu.Lock()
+		if batch.err == nil {
+			batch.err = err
+		}
+		batch.mu.Unlock()
+		return
+	}
+	if batch.mog.autoTimestamps {
+		doc, _ = timestampDoc(doc, true) // on error doc unchanged, Commit reports the error
+	}
+	model := mongo.NewInsertOneModel()
+	model.SetDocument(doc)
+	batch.add(model)
+}
+
+// AddUpdate adds matching criteria and update doc to the batch.
+func (batch *BulkBatch) AddUpdate(criteria, update interface{}) {
+	if batch.mog.autoTimestamps {
+		update, _ = timestampUpdate(update)
+	}
+	model := mongo.NewUpdateManyModel()
+	model.SetFilter(criteria)
+	model.SetUpdate(update)
+	batch.add(model)
+}
+
+func (batch *BulkBatch) add(model mongo.WriteModel) {
+	batch.mu.Lock()
+	batch.models = append(batch.models, model)
+	batch.mu.Unlock()
+}
+
+// Len returns the number of models waiting to be committed.
+func (batch *BulkBatch) Len() int {
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+	return len(batch.models)
+}
+
+// Commit executes bulk write using the models in the batch. The batch is emptied and can be reused.
+// If a BeforeInsert failed since the last Commit, its error is returned and nothing is written.
+// If mog had a write rate limit, Commit waits until it allows all models in the batch.
+// If mog was in dry run mode, the models are logged instead of written and the result counts are 0.
+// If mog had EnableAudit on, an audit entry is written for each model written.
+func (batch *BulkBatch) Commit() (*mongo.BulkWriteResult, error) {
+	batch.mu.Lock()
+	models, hookErr := batch.models, batch.err
+	batch.models = make([]mongo.WriteModel, 0, cap(models))
+	batch.err = nil
+	batch.mu.Unlock()
+	if hookErr != nil {
+		return nil, hookErr
+	}
+	if len(models) == 0 {
+		return nil, errors.New("bulk batch is empty")
+	}
+	if batch.mog.dryRun {
+		return &mongo.BulkWriteResult{}, batch.mog.dryRunModels(models)
+	}
+	if err := batch.mog.waitToWrite(len(models)); err != nil {
+		return nil, err
+	}
+	var result *mongo.BulkWriteResult
+	err := batch.mog.guard(func() error {
+		var err error
+		result, err = batch.mog.collection.BulkWrite(batch.mog.ctx, models)
+		return err
+	})
+	auditor := batch.mog.Clone() // Commit may run concurrently, writing audit entries changes mog.ctx while timed
+	if auditErr := auditor.auditModels(writtenModels(models, err, true)); auditErr != nil {
+		return result, auditErr
+	}
+	return result, err
+}
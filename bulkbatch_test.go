@@ -0,0 +1,61 @@
+package mog
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// run with -race to verify concurrent adds
+func Test_BulkBatch(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	batch := mog1.NewBulkBatch(100)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				batch.AddInsert(Property{Id: NewDocId(), City: "Wonder"})
+			}
+		}()
+	}
+	wg.Wait()
+	result, err := batch.Commit()
+	if err != nil || result.InsertedCount != 100 {
+		t.Fatal("BulkBatch Commit Failed", err, result)
+	}
+	if batch.Len() != 0 {
+		t.Fatal("BulkBatch Not Reset After Commit", batch.Len())
+	}
+	count, _ := mog1.Count(m{"city": "Wonder"})
+	if count != 100 {
+		t.Fatal("BulkBatch Wrong Count", count)
+	}
+	fmt.Println("bulkBatch successful")
+}
+
+func Test_BulkBatchRateLimit(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	mog1.SetWriteRateLimit(10)
+	batch := mog1.NewBulkBatch(10)
+	start := time.Now()
+	for c := 0; c < 2; c++ {
+		for i := 0; i < 10; i++ {
+			batch.AddInsert(Property{Id: NewDocId(), City: "Wonder"})
+		}
+		if _, err := batch.Commit(); err != nil {
+			t.Fatal("Rate Limited BulkBatch Commit Failed", err)
+		}
+	}
+	// 1st commit of 10 is written immediately, 2nd waits 1 second
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Fatal("BulkBatch Rate Limit Not Applied", elapsed)
+	}
+	fmt.Println("bulkBatch rate limit successful")
+}
@@ -0,0 +1,145 @@
+package mog
+
+import (
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BulkWriter inserts large numbers of docs using concurrent bulk writes.
+// Docs passed to Add are grouped into batches of batchSize, each batch written by one of a fixed number of workers.
+// Docs are inserted unordered, failed inserts do not stop other inserts. In dry run mode (see DryRun) docs are logged.
+// With EnableAudit, an audit entry is written for each doc inserted.
+// Add is safe for concurrent use. Create using NewBulkWriter, call Close when done.
+type BulkWriter struct {
+	mog       *Mog // clone of the creating mog, for its context, collection, write rate limit, circuit breaker, dry run, timestamps and audit
+	batchSize int
+	batches   chan []mongo.WriteModel
+	workers   sync.WaitGroup
+	sending   sync.RWMutex // held by Add while sending, Close waits for sends before closing batches
+	mu        sync.Mutex
+	pending   []mongo.WriteModel
+	closed    bool
+	inserted  int64
+	firstErr  error
+}
+
+// NewBulkWriter creates a BulkWriter for mog's current collection and starts its workers.
+// The write rate limit of mog (see SetWriteRateLimit) is shared by the workers.
+// Ex: writer := NewBulkWriter(mog, 8, 1000)
+func NewBulkWriter(mog *Mog, workers, batchSize int) *BulkWriter {
+	if workers < 1 {
+		workers = 1
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	writer := &BulkWriter{
+		mog:       mog.Clone(),
+		batchSize: batchSize,
+		batches:   make(chan []mongo.WriteModel, workers),
+		pending:   make([]mongo.WriteModel, 0, batchSize),
+	}
+	for i := 0; i < workers; i++ {
+		writer.workers.Add(1)
+		go writer.work()
+	}
+	return writer
+}
+
+// Add queues doc to be inserted. When a batch is full it is handed to a worker, blocking while all workers are busy.
+// Returns error if the writer is closed or its context is done, or if BeforeInsert of doc fails (doc is not queued).
+func (writer *BulkWriter) Add(doc interface{}) error {
+	if err := beforeInsert([]interface{}{doc}); err != nil {
+		return err
+	}
+	if writer.mog.autoTimestamps {
+		var err error
+		if doc, err = timestampDoc(doc, true); err != nil {
+			return err
+		}
+	}
+	model := mongo.NewInsertOneModel()
+	model.SetDocument(doc)
+	writer.sending.RLock()
+	defer writer.sending.RUnlock()
+	writer.mu.Lock()
+	if writer.closed {
+		writer.mu.Unlock()
+		return errors.New("bulk writer is closed")
+	}
+	writer.pending = append(writer.pending, model)
+	var batch []mongo.WriteModel
+	if len(writer.pending) >= writer.batchSize {
+		batch = writer.pending
+		writer.pending = make([]mongo.WriteModel, 0, writer.batchSize)
+	}
+	writer.mu.Unlock()
+	if batch == nil {
+		return nil
+	}
+	select {
+	case writer.batches <- batch:
+		return nil
+	case <-writer.mog.ctx.Done():
+		return writer.mog.ctx.Err()
+	}
+}
+
+// Close writes remaining docs, waits for the workers to finish, and returns the number of docs inserted.
+// If any inserts failed, the 1st error is returned (after all batches are written).
+func (writer *BulkWriter) Close() (int64, error) {
+	writer.sending.Lock()
+	writer.mu.Lock()
+	if writer.closed {
+		inserted := writer.inserted
+		writer.mu.Unlock()
+		writer.sending.Unlock()
+		return inserted, errors.New("bulk writer is closed")
+	}
+	writer.closed = true
+	batch := writer.pending
+	writer.pending = nil
+	writer.mu.Unlock()
+	if len(batch) > 0 {
+		writer.batches <- batch
+	}
+	close(writer.batches)
+	writer.sending.Unlock()
+	writer.workers.Wait()
+	return writer.inserted, writer.firstErr
+}
+
+// work writes batches until the batches channel is closed.
+func (writer *BulkWriter) work() {
+	defer writer.workers.Done()
+	auditor := writer.mog.Clone() // per worker, writing audit entries changes mog.ctx while timed
+	for batch := range writer.batches {
+		var inserted int64
+		var err error
+		if writer.mog.dryRun {
+			err = writer.mog.dryRunModels(batch)
+		} else if err = writer.mog.waitToWrite(len(batch)); err == nil {
+			var result *mongo.BulkWriteResult
+			err = writer.mog.guard(func() error {
+				var err error
+				result, err = writer.mog.collection.BulkWrite(writer.mog.ctx, batch, options.BulkWrite().SetOrdered(false))
+				return err
+			})
+			if result != nil {
+				inserted = result.InsertedCount
+			}
+			if auditErr := auditor.auditModels(writtenModels(batch, err, false)); auditErr != nil && err == nil {
+				err = auditErr
+			}
+		}
+		writer.mu.Lock()
+		writer.inserted += inserted
+		if err != nil && writer.firstErr == nil {
+			writer.firstErr = err
+		}
+		writer.mu.Unlock()
+	}
+}
@@ -0,0 +1,43 @@
+package mog
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// run with -race to verify concurrent adds
+func Test_BulkWriter(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	mog1.Insert(Property{Id: "7"})
+
+	writer := NewBulkWriter(mog1, 4, 50)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				if err := writer.Add(Property{Id: strconv.Itoa(w*250 + i), City: "Wonder"}); err != nil {
+					t.Error("BulkWriter Add Failed", err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	inserted, err := writer.Close()
+	if err == nil || inserted != 999 {
+		t.Fatal("BulkWriter Close Failed", err, inserted)
+	}
+	if err = writer.Add(Property{Id: "x"}); err == nil {
+		t.Fatal("BulkWriter Add After Close Not Rejected")
+	}
+	count, _ := mog1.Count(m{"city": "Wonder"})
+	if count != 999 {
+		t.Fatal("BulkWriter Wrong Count", count)
+	}
+	fmt.Println("bulkWriter successful")
+}
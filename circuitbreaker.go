@@ -0,0 +1,87 @@
+package mog
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrCircuitOpen is returned, without contacting the server, while the circuit breaker is open, see SetCircuitBreaker.
+var ErrCircuitOpen = errors.New("mog circuit breaker open, database unavailable")
+
+// circuitBreaker fails operations fast after threshold consecutive failures, see SetCircuitBreaker.
+// After coolDown, 1 trial operation is allowed (half open). Success closes the circuit, failure opens it again.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	coolDown  time.Duration
+	failures  int       // consecutive failures
+	openedAt  time.Time // zero if closed
+	trial     bool      // true while the trial operation is running
+}
+
+// allow returns ErrCircuitOpen if the circuit is open and the operation must not run.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.openedAt.IsZero() {
+		return nil
+	}
+	if cb.trial || time.Since(cb.openedAt) < cb.coolDown {
+		return ErrCircuitOpen
+	}
+	cb.trial = true
+	return nil
+}
+
+// record updates the circuit using the outcome of an allowed operation.
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.trial = false
+	if !circuitFailure(err) {
+		cb.failures = 0
+		cb.openedAt = time.Time{}
+		return
+	}
+	cb.failures++
+	if cb.failures >= cb.threshold {
+		cb.openedAt = time.Now()
+	}
+}
+
+// circuitFailure returns true if err indicates the server is unavailable (network errors and timeouts).
+// Other errors, such as duplicate keys or mongo.ErrNoDocuments, show the server is responding.
+func circuitFailure(err error) bool {
+	return err != nil && (mongo.IsNetworkError(err) || mongo.IsTimeout(err))
+}
+
+// SetCircuitBreaker turns on a circuit breaker, opened after threshold consecutive network errors or timeouts.
+// While open, operations fail fast with ErrCircuitOpen instead of waiting on an unavailable server.
+// After coolDown, 1 operation is tried, the circuit closes if it succeeds, else stays open for another coolDown.
+// Applies to all doc reads and writes, retries count as 1 operation. For methods streaming results
+// (Find, FindUntil, FindChan, AggRun, AggCsvStream) only opening the cursor is guarded.
+// Index, admin, Watch and GridFS methods are not guarded.
+// The breaker is shared by mog and its clones. Use threshold 0 to remove the breaker.
+func (mog *Mog) SetCircuitBreaker(threshold int, coolDown time.Duration) {
+	if threshold <= 0 {
+		mog.breaker = nil
+		return
+	}
+	mog.breaker = &circuitBreaker{threshold: threshold, coolDown: coolDown}
+}
+
+// guard runs op through the circuit breaker, if one is set.
+func (mog *Mog) guard(op func() error) error {
+	if mog.breaker == nil {
+		return op()
+	}
+	if err := mog.breaker.allow(); err != nil {
+		return err
+	}
+	err := op()
+	mog.breaker.record(err)
+	return err
+}
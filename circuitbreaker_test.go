@@ -0,0 +1,62 @@
+package mog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// uses a server address where nothing is listening
+func Test_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	uri := "mongodb://localhost:1/?serverSelectionTimeoutMS=200"
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal("Mongo Connect Failed", err)
+	}
+	defer client.Disconnect(ctx)
+	mog1 := NewMog(ctx, client.Database("demo"), "property")
+
+	mog1.SetCircuitBreaker(2, 300*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err = mog1.Count(m{}); err == nil || err == ErrCircuitOpen {
+			t.Fatal("CircuitBreaker Opened Too Soon", i, err)
+		}
+	}
+	start := time.Now()
+	if err = mog1.Clone().Insert(Property{Id: "p1"}); err != ErrCircuitOpen {
+		t.Fatal("CircuitBreaker Not Open", err)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Fatal("CircuitBreaker Did Not Fail Fast", time.Since(start))
+	}
+	if err = mog1.FindUntil(nil, func(raw bson.Raw) (bool, error) { return false, nil }); err != ErrCircuitOpen {
+		t.Fatal("CircuitBreaker Not Applied To FindUntil", err)
+	}
+	if _, findErr := mog1.FindChan(nil); findErr() != ErrCircuitOpen {
+		t.Fatal("CircuitBreaker Not Applied To FindChan", findErr())
+	}
+	if _, err = mog1.Checksum(nil); err != ErrCircuitOpen {
+		t.Fatal("CircuitBreaker Not Applied To Checksum", err)
+	}
+	if _, err = mog1.Deduplicate("address"); err != ErrCircuitOpen {
+		t.Fatal("CircuitBreaker Not Applied To Deduplicate", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if err = mog1.Find(nil); err == nil || err == ErrCircuitOpen { // trial operation after cool down
+		t.Fatal("CircuitBreaker Did Not Allow Trial", err)
+	}
+	if _, err = mog1.Count(m{}); err != ErrCircuitOpen {
+		t.Fatal("CircuitBreaker Not Reopened After Failed Trial", err)
+	}
+	mog1.SetCircuitBreaker(0, 0)
+	if _, err = mog1.Count(m{}); err == ErrCircuitOpen {
+		t.Fatal("CircuitBreaker Not Removed", err)
+	}
+	fmt.Println("circuit breaker successful")
+}
@@ -0,0 +1,181 @@
+package mog
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// --- Criteria Helpers ----------------------------------------------------
+
+// Regex returns criteria matching docs where field matches regular expression pattern.
+// Parm "opts" are regex option letters in any order: i (ignore case), m (multiline), s (dot matches newline),
+// x (ignore whitespace in pattern). Duplicate or invalid letters are dropped.
+// Ex: FindAll(Regex("address", "^\\d+ willow", "i"), &props)
+func Regex(field, pattern, opts string) bson.M {
+	return bson.M{field: primitive.Regex{Pattern: pattern, Options: regexOptions(opts)}}
+}
+
+// EqCI returns criteria matching docs where field equals value ignoring case.
+// Special regex characters in value are escaped. The match cannot use an index efficiently,
+// for large collections use SetCollation(locale, true) with plain criteria and an index having the same collation.
+// Ex: FindAll(EqCI("city", "las vegas"), &props)
+func EqCI(field, value string) bson.M {
+	return Regex(field, "^"+regexp.QuoteMeta(value)+"$", "i")
+}
+
+// Between returns criteria matching docs where from <= field < to. A nil from or to leaves that end open.
+// Works with any comparable values: numbers, time.Time, strings such as "yyyy-mm-dd" dates.
+// Ex: FindAll(Between("sum_fld1", 5, 10), &props)
+func Between(field string, from, to interface{}) bson.M {
+	rangeCriteria := bson.M{}
+	if from != nil {
+		rangeCriteria["$gte"] = from
+	}
+	if to != nil {
+		rangeCriteria["$lt"] = to
+	}
+	return bson.M{field: rangeCriteria}
+}
+
+// DateRange returns criteria matching docs where date field is on any day from thru to (both inclusive).
+// Days start at midnight in the location of from and to.
+// Ex: DateRange("created", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC))
+func DateRange(field string, from, to time.Time) bson.M {
+	return Between(field, startOfDay(from), startOfDay(to).AddDate(0, 0, 1))
+}
+
+// DateRangeStr is DateRange for fields holding "yyyy-mm-dd" strings (values with a time suffix also match).
+// Returns error if from or to is not a valid "yyyy-mm-dd" date.
+// Ex: DateRangeStr("date_added", "2018-01-01", "2018-12-31")
+func DateRangeStr(field, from, to string) (bson.M, error) {
+	if _, err := time.Parse(dateLayout, from); err != nil {
+		return nil, err
+	}
+	toDate, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return nil, err
+	}
+	return Between(field, from, toDate.AddDate(0, 0, 1).Format(dateLayout)), nil
+}
+
+// Example returns criteria matching docs having the values of doc's non-zero fields (query-by-example).
+// Field names come from bson tags (lower case field name if no tag), fields tagged "-" and unexported fields are skipped.
+// Non-zero nested struct fields (other than time.Time, etc.) are matched field by field using dotted names.
+// Parm "doc" is a struct or pointer to struct. An error is returned if doc is not a struct or has no non-zero fields
+// (criteria matching every doc is rarely intended).
+// Ex: FindAll(Example(Property{City: "Wonder", St: "MT"}), &props)
+func Example(doc interface{}) (bson.M, error) {
+	docValue := reflect.ValueOf(doc)
+	if docValue.Kind() == reflect.Ptr {
+		docValue = docValue.Elem()
+	}
+	if docValue.Kind() != reflect.Struct {
+		return nil, errors.New("Example doc must be a struct")
+	}
+	criteria := bson.M{}
+	exampleFields(docValue, "", criteria)
+	if len(criteria) == 0 {
+		return nil, errors.New("Example doc has no non-zero fields")
+	}
+	return criteria, nil
+}
+
+// exampleFields adds the non-zero fields of struct docValue to criteria, field names prefixed with prefix.
+func exampleFields(docValue reflect.Value, prefix string, criteria bson.M) {
+	docType := docValue.Type()
+	for i := 0; i < docType.NumField(); i++ {
+		field := docType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("bson")
+		fldName := strings.Split(tag, ",")[0]
+		if fldName == "-" {
+			continue
+		}
+		if fldName == "" {
+			fldName = strings.ToLower(field.Name)
+		}
+		fldValue := docValue.Field(i)
+		if fldValue.IsZero() || (fldValue.Kind() == reflect.Slice || fldValue.Kind() == reflect.Map) && fldValue.Len() == 0 {
+			continue
+		}
+		if fldValue.Kind() == reflect.Ptr {
+			fldValue = fldValue.Elem()
+		}
+		if isExampleStruct(fldValue.Type()) {
+			if strings.Contains(tag, ",inline") {
+				exampleFields(fldValue, prefix, criteria)
+			} else {
+				exampleFields(fldValue, prefix+fldName+".", criteria)
+			}
+			continue
+		}
+		criteria[prefix+fldName] = fldValue.Interface()
+	}
+}
+
+// GeoWithinPolygon returns criteria matching docs where GeoJSON field (point, line, etc.) is inside polygon coords.
+// Parm "coords" are the polygon's corners as [longitude, latitude] pairs, closed automatically if last != first.
+// Ex: GeoWithinPolygon("location", [][2]float64{{-115.3, 36.0}, {-115.0, 36.0}, {-115.0, 36.3}})
+func GeoWithinPolygon(field string, coords [][2]float64) bson.M {
+	ring := make([][2]float64, len(coords), len(coords)+1)
+	copy(ring, coords)
+	if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+		ring = append(ring, ring[0])
+	}
+	geometry := bson.M{"type": "Polygon", "coordinates": [][][2]float64{ring}}
+	return bson.M{field: bson.M{"$geoWithin": bson.M{"$geometry": geometry}}}
+}
+
+// GeoWithinBox returns criteria matching docs where GeoJSON field is inside the box (such as a map viewport)
+// having corners bottomLeft and topRight, each a [longitude, latitude] pair.
+// Ex: GeoWithinBox("location", [2]float64{-115.3, 36.0}, [2]float64{-115.0, 36.3})
+func GeoWithinBox(field string, bottomLeft, topRight [2]float64) bson.M {
+	return GeoWithinPolygon(field, [][2]float64{
+		bottomLeft,
+		{topRight[0], bottomLeft[1]},
+		topRight,
+		{bottomLeft[0], topRight[1]},
+	})
+}
+
+// isExampleStruct returns true if values of fldType are matched field by field by Example.
+// Structs without exported fields (such as time.Time and primitive.Decimal128) are matched as a whole.
+func isExampleStruct(fldType reflect.Type) bool {
+	if fldType.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < fldType.NumField(); i++ {
+		if fldType.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// dateLayout is the layout of "yyyy-mm-dd" date strings.
+const dateLayout = "2006-01-02"
+
+// startOfDay returns midnight of t's day in t's location.
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// regexOptions returns valid option letters of opts, without duplicates, in the alphabetical order required by bson.
+func regexOptions(opts string) string {
+	var valid strings.Builder
+	for _, letter := range "imsx" {
+		if strings.ContainsRune(opts, letter) {
+			valid.WriteRune(letter)
+		}
+	}
+	return valid.String()
+}
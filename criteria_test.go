@@ -0,0 +1,123 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_Regex(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	if opts := regexOptions("xiqi"); opts != "ix" {
+		t.Fatal("Regex Options Failed", opts)
+	}
+	var props []Property
+	if err := mog1.FindAll(Regex("address", "^\\d+ (willow|angel)", "i"), &props, "_id"); err != nil || len(props) != 2 {
+		t.Fatal("Regex Failed", err, len(props))
+	}
+	if err := mog1.FindAll(EqCI("city", "las VEGAS"), &props); err != nil || len(props) != 1 || props[0].Id != "p3" {
+		t.Fatal("EqCI Failed", err, props)
+	}
+	if err := mog1.FindAll(EqCI("city", "las"), &props); err != nil || len(props) != 0 {
+		t.Fatal("EqCI Partial Match Failed", err, props)
+	}
+	fmt.Println("regex successful")
+}
+
+func Test_Between(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	var props []Property
+	if err := mog1.FindAll(Between("sum_fld1", 7, 13), &props); err != nil || len(props) != 2 {
+		t.Fatal("Between Failed", err, len(props))
+	}
+	if err := mog1.FindAll(Between("sum_fld1", 10, nil), &props); err != nil || len(props) != 2 {
+		t.Fatal("Between Open Ended Failed", err, len(props))
+	}
+	criteria, err := DateRangeStr("date_added", "2018-03-11", "2019-04-04")
+	if err != nil {
+		t.Fatal("DateRangeStr Failed", err)
+	}
+	if err = mog1.FindAll(criteria, &props); err != nil || len(props) != 2 {
+		t.Fatal("DateRangeStr Find Failed", err, len(props))
+	}
+	if _, err = DateRangeStr("date_added", "2018-3-11", "2019-04-04"); err == nil {
+		t.Fatal("DateRangeStr Invalid Date Not Rejected")
+	}
+
+	added := time.Date(2019, 4, 4, 15, 30, 0, 0, time.UTC)
+	if err = mog1.Insert(m{"_id": "p4", "added": added}); err != nil {
+		t.Fatal("Insert Failed", err)
+	}
+	day := time.Date(2019, 4, 4, 0, 0, 0, 0, time.UTC)
+	if count, err := mog1.Count(DateRange("added", day, day)); err != nil || count != 1 {
+		t.Fatal("DateRange Failed", err, count)
+	}
+	if count, err := mog1.Count(DateRange("added", day.AddDate(0, 0, 1), day.AddDate(0, 0, 5))); err != nil || count != 0 {
+		t.Fatal("DateRange Outside Failed", err, count)
+	}
+	fmt.Println("between successful")
+}
+
+func Test_Example(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	criteria, err := Example(Property{City: "Wonder", St: "MT", SumFld1: 10})
+	if err != nil || len(criteria) != 3 {
+		t.Fatal("Example Failed", err, criteria)
+	}
+	var props []Property
+	if err = mog1.FindAll(criteria, &props); err != nil || len(props) != 1 || props[0].Id != "p2" {
+		t.Fatal("Example Find Failed", err, props)
+	}
+	type Contact struct {
+		Name  string `bson:"name"`
+		Phone string `bson:"phone"`
+	}
+	type Owner struct {
+		Contact `bson:",inline"`
+		Mail    Contact   `bson:"mail"`
+		Since   time.Time `bson:"since"`
+	}
+	since := time.Date(2019, 4, 4, 0, 0, 0, 0, time.UTC)
+	criteria, err = Example(&Owner{Contact: Contact{Name: "Joe"}, Mail: Contact{Phone: "555"}, Since: since})
+	if err != nil || len(criteria) != 3 || criteria["name"] != "Joe" || criteria["mail.phone"] != "555" || criteria["since"] != since {
+		t.Fatal("Example Nested Failed", err, criteria)
+	}
+	if _, err = Example(Property{}); err == nil {
+		t.Fatal("Example Zero Doc Not Rejected")
+	}
+	if _, err = Example("Wonder"); err == nil {
+		t.Fatal("Example Non Struct Not Rejected")
+	}
+	fmt.Println("example successful")
+}
+
+func Test_GeoWithin(t *testing.T) {
+	mog1, disconnect := testMog(t, "place")
+	defer disconnect()
+	point := func(lng, lat float64) m { return m{"type": "Point", "coordinates": []float64{lng, lat}} }
+	mog1.Insert(
+		m{"_id": "strip", "location": point(-115.17, 36.11)},
+		m{"_id": "downtown", "location": point(-115.14, 36.17)},
+		m{"_id": "reno", "location": point(-119.81, 39.53)},
+	)
+
+	var places []m
+	criteria := GeoWithinBox("location", [2]float64{-115.3, 36.0}, [2]float64{-115.0, 36.3})
+	if err := mog1.FindAll(criteria, &places, "_id"); err != nil || len(places) != 2 {
+		t.Fatal("GeoWithinBox Failed", err, places)
+	}
+	criteria = GeoWithinPolygon("location", [][2]float64{{-115.3, 36.0}, {-115.0, 36.0}, {-115.3, 36.3}})
+	if err := mog1.FindAll(criteria, &places); err != nil || len(places) != 1 || places[0]["_id"] != "strip" {
+		t.Fatal("GeoWithinPolygon Failed", err, places)
+	}
+	fmt.Println("geoWithin successful")
+}
@@ -0,0 +1,80 @@
+package mog
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// --- Dry Run Methods ----------------------------------------------------
+
+// ErrDryRun is returned by FindOneAnd.. methods in dry run mode, see DryRun.
+var ErrDryRun = errors.New("FindOneAnd.. methods not available in dry run mode")
+
+// DryRun turns dry run mode on or off. In dry run mode no write changes the collection. Instead, each write is
+// logged with the collection, criteria, update or new doc, and the count of docs matching criteria.
+// Messages go to the Logger set by SetLogger, or the standard logger (stderr) if none is set.
+// Update, UpdateId, Replace, Save, Insert, Delete.., Deduplicate, Restore, versioned writes and bulk writes
+// (BulkWrite, auto flushes, BulkFromChannel, BulkBatch, BulkWriter) are logged, counts returned are 0.
+// FindOneAndUpdate, FindOneAndReplace and FindOneAndDelete return ErrDryRun, the doc they load can not be known.
+// Useful for testing migration scripts. Remains in effect until DryRun(false) is called.
+func (mog *Mog) DryRun(on bool) {
+	mog.dryRun = on
+}
+
+// dryRunLog logs write op that would be executed. If criteria is not nil, the count of docs matching it is included.
+func (mog *Mog) dryRunLog(op string, criteria, doc interface{}) error {
+	msg := "mog dry run " + op + " " + mog.collectionName
+	if criteria != nil {
+		count, err := mog.collection.CountDocuments(mog.ctx, criteria)
+		if err != nil {
+			return err
+		}
+		msg += fmt.Sprintf(" criteria: %s matched: %d", dryRunJSON(criteria), count)
+	}
+	if doc != nil {
+		msg += " doc: " + dryRunJSON(doc)
+	}
+	logger := mog.log()
+	if mog.logger == nil {
+		logger = NewStdLogger(log.Default())
+	}
+	logger.Printf("%s", msg)
+	return nil
+}
+
+// dryRunModels logs each bulk write model that would be executed.
+func (mog *Mog) dryRunModels(models []mongo.WriteModel) error {
+	for _, model := range models {
+		var err error
+		switch m := model.(type) {
+		case *mongo.InsertOneModel:
+			err = mog.dryRunLog("bulk insert", nil, m.Document)
+		case *mongo.UpdateManyModel:
+			err = mog.dryRunLog("bulk update", m.Filter, m.Update)
+		case *mongo.UpdateOneModel:
+			err = mog.dryRunLog("bulk update one", m.Filter, m.Update)
+		case *mongo.ReplaceOneModel:
+			err = mog.dryRunLog("bulk replace", m.Filter, m.Replacement)
+		case *mongo.DeleteManyModel:
+			err = mog.dryRunLog("bulk delete", m.Filter, nil)
+		case *mongo.DeleteOneModel:
+			err = mog.dryRunLog("bulk delete one", m.Filter, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dryRunJSON returns v as extended JSON, or formatted by fmt if v cannot be marshaled.
+func dryRunJSON(v interface{}) string {
+	if b, err := bson.MarshalExtJSON(v, false, false); err == nil {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,157 @@
+package mog
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// --- Explain Methods ----------------------------------------------------
+
+// ExplainResult is the decoded query plan returned by ExplainFind and AggExplain.
+type ExplainResult struct {
+	WinningPlan         bson.M       // plan chosen by the query planner
+	IndexesUsed         []string     // names of indexes scanned by the winning plan, empty if none
+	CollScan            bool         // true if the winning plan scans the whole collection
+	NReturned           int64        // docs returned (verbosity executionStats or allPlansExecution)
+	KeysExamined        int64        // index keys examined
+	DocsExamined        int64        // docs examined
+	ExecutionTimeMillis int64        // time to execute the winning plan
+	Stages              []StageStats // per stage stats, AggExplain only, empty if whole pipeline run by the query planner
+	Raw                 bson.M       // complete explain output
+}
+
+// StageStats are the execution stats of an aggregation stage, see AggExplain.
+type StageStats struct {
+	Stage                       string `bson:"-"` // stage name, e.g. "$cursor", "$group"
+	NReturned                   int64  `bson:"nReturned"`
+	ExecutionTimeMillisEstimate int64  `bson:"executionTimeMillisEstimate"`
+}
+
+// explainOutput is the part of the server's explain output decoded into ExplainResult.
+type explainOutput struct {
+	QueryPlanner struct {
+		WinningPlan bson.M `bson:"winningPlan"`
+	} `bson:"queryPlanner"`
+	ExecutionStats struct {
+		NReturned           int64 `bson:"nReturned"`
+		ExecutionTimeMillis int64 `bson:"executionTimeMillis"`
+		TotalKeysExamined   int64 `bson:"totalKeysExamined"`
+		TotalDocsExamined   int64 `bson:"totalDocsExamined"`
+	} `bson:"executionStats"`
+	Stages []bson.Raw `bson:"stages"` // aggregation only
+}
+
+// ExplainFind returns the query plan for a find using criteria, without returning any docs.
+// Parm "verbosity" is "queryPlanner", "executionStats" or "allPlansExecution", "" defaults to "executionStats".
+// Settings used by Find (Keep/Omit, SetLimit, SetSkip, SetHint, SetCollation, SetMaxTime) are applied and reset.
+// Ex: plan, err := ExplainFind(bson.M{"st": "NV"}, "", "city")
+func (mog *Mog) ExplainFind(criteria interface{}, verbosity string, sortFlds ...string) (ExplainResult, error) {
+	if criteria == nil {
+		criteria = bson.M{}
+	}
+	criteria = mog.notDeleted(criteria)
+	if verbosity == "" {
+		verbosity = "executionStats"
+	}
+	findOptions := mog.findOptions(sortFlds)
+	findCmd := bson.D{{Key: "find", Value: mog.collectionName}, {Key: "filter", Value: criteria}}
+	if findOptions.Sort != nil {
+		findCmd = append(findCmd, bson.E{Key: "sort", Value: findOptions.Sort})
+	}
+	if findOptions.Projection != nil {
+		findCmd = append(findCmd, bson.E{Key: "projection", Value: findOptions.Projection})
+	}
+	if findOptions.Limit != nil {
+		findCmd = append(findCmd, bson.E{Key: "limit", Value: *findOptions.Limit})
+	}
+	if findOptions.Skip != nil {
+		findCmd = append(findCmd, bson.E{Key: "skip", Value: *findOptions.Skip})
+	}
+	if findOptions.Hint != nil {
+		findCmd = append(findCmd, bson.E{Key: "hint", Value: findOptions.Hint})
+	}
+	if findOptions.Collation != nil {
+		findCmd = append(findCmd, bson.E{Key: "collation", Value: bson.Raw(findOptions.Collation.ToDocument())})
+	}
+	cmd := bson.D{{Key: "explain", Value: findCmd}, {Key: "verbosity", Value: verbosity}}
+	if findOptions.MaxTime != nil {
+		cmd = append(cmd, bson.E{Key: "maxTimeMS", Value: findOptions.MaxTime.Milliseconds()})
+	}
+	return mog.explain(cmd)
+}
+
+// AggExplain runs the aggregation pipeline (mog.AggPipeline) with explain verbosity "executionStats" and returns
+// the query plan of the initial (query) stage plus execution stats of each stage.
+// No docs are returned. The pipeline is not changed.
+func (mog *Mog) AggExplain() (ExplainResult, error) {
+	aggCmd := bson.D{
+		{Key: "aggregate", Value: mog.collectionName},
+		{Key: "pipeline", Value: mog.notDeletedPipeline(mog.AggPipeline)},
+		{Key: "cursor", Value: bson.M{}},
+	}
+	cmd := bson.D{{Key: "explain", Value: aggCmd}, {Key: "verbosity", Value: "executionStats"}}
+	return mog.explain(cmd)
+}
+
+// explain runs explain command cmd and decodes the output.
+func (mog *Mog) explain(cmd bson.D) (ExplainResult, error) {
+	var result ExplainResult
+	raw, err := mog.db.RunCommand(mog.ctx, cmd).Raw()
+	if err != nil {
+		return result, err
+	}
+	var output explainOutput
+	if err = bson.Unmarshal(raw, &output); err != nil {
+		return result, err
+	}
+	if err = bson.Unmarshal(raw, &result.Raw); err != nil {
+		return result, err
+	}
+	for _, stageRaw := range output.Stages {
+		elements, err := stageRaw.Elements()
+		if err != nil || len(elements) == 0 {
+			return result, err
+		}
+		stage := StageStats{Stage: elements[0].Key()}
+		if stage.Stage == "$cursor" { // query stage holds queryPlanner and executionStats
+			if err = stageRaw.Lookup(stage.Stage).Unmarshal(&output); err != nil {
+				return result, err
+			}
+		}
+		if err = bson.Unmarshal(stageRaw, &stage); err != nil {
+			return result, err
+		}
+		result.Stages = append(result.Stages, stage)
+	}
+	result.WinningPlan = output.QueryPlanner.WinningPlan
+	if queryPlan, ok := result.WinningPlan["queryPlan"].(bson.M); ok { // slot based execution engine
+		result.WinningPlan = queryPlan
+	}
+	result.IndexesUsed, result.CollScan = planStages(result.WinningPlan, nil, false)
+	result.NReturned = output.ExecutionStats.NReturned
+	result.KeysExamined = output.ExecutionStats.TotalKeysExamined
+	result.DocsExamined = output.ExecutionStats.TotalDocsExamined
+	result.ExecutionTimeMillis = output.ExecutionStats.ExecutionTimeMillis
+	return result, nil
+}
+
+// planStages walks plan (including nested input stages) collecting index names and whether a collection scan is used.
+func planStages(plan interface{}, indexes []string, collScan bool) ([]string, bool) {
+	switch p := plan.(type) {
+	case bson.M:
+		switch p["stage"] {
+		case "IXSCAN", "EXPRESS_IXSCAN", "COUNT_SCAN", "DISTINCT_SCAN":
+			if name, ok := p["indexName"].(string); ok {
+				indexes = append(indexes, name)
+			}
+		case "COLLSCAN":
+			collScan = true
+		}
+		indexes, collScan = planStages(p["inputStage"], indexes, collScan)
+		indexes, collScan = planStages(p["inputStages"], indexes, collScan)
+	case bson.A:
+		for _, val := range p {
+			indexes, collScan = planStages(val, indexes, collScan)
+		}
+	}
+	return indexes, collScan
+}
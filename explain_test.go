@@ -0,0 +1,55 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_ExplainFind(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	plan, err := mog1.ExplainFind(m{"st": "NV"}, "")
+	if err != nil || !plan.CollScan || len(plan.IndexesUsed) != 0 || plan.DocsExamined != 3 {
+		t.Fatal("ExplainFind CollScan Failed", err, plan.CollScan, plan.IndexesUsed, plan.DocsExamined)
+	}
+	if _, err = mog1.CreateIndex(bson.D{{Key: "st", Value: 1}}, IndexOpts{}); err != nil {
+		t.Fatal("CreateIndex Failed", err)
+	}
+	plan, err = mog1.ExplainFind(m{"st": "NV"}, "executionStats", "st")
+	if err != nil || plan.CollScan || len(plan.IndexesUsed) != 1 || plan.IndexesUsed[0] != "st_1" {
+		t.Fatal("ExplainFind Index Failed", err, plan.CollScan, plan.IndexesUsed)
+	}
+	if plan.NReturned != plan.DocsExamined {
+		t.Fatal("ExplainFind Stats Failed", plan.NReturned, plan.DocsExamined)
+	}
+	fmt.Println("explainFind successful")
+}
+
+func Test_AggExplain(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.AggStart()
+	mog1.AggStage("match", bson.M{"st": "MT"})
+	mog1.AggTotal("city", "sum_fld1")
+	mog1.AggSort("_id")
+	plan, err := mog1.AggExplain()
+	if err != nil {
+		t.Fatal("AggExplain Failed", err)
+	}
+	if len(mog1.AggPipeline) != 3 {
+		t.Fatal("AggExplain Changed Pipeline", mog1.AggPipeline)
+	}
+	if plan.WinningPlan == nil && len(plan.Stages) == 0 {
+		t.Fatal("AggExplain No Plan Or Stages", plan.Raw)
+	}
+	for _, stage := range plan.Stages {
+		fmt.Println(stage.Stage, stage.NReturned, stage.ExecutionTimeMillisEstimate)
+	}
+	fmt.Println("aggExplain successful")
+}
@@ -0,0 +1,84 @@
+package mog
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FindChan runs a query like Find and sends each doc found to the returned channel.
+// The channel is closed after the last doc, an error, or cancellation of mog's context.
+// The returned func returns the error (nil if none) that ended the query, call it after the channel is closed.
+// If the consumer stops reading early, cancel mog's context so the cursor is closed.
+// Docs are bson.Raw, use bson.Unmarshal to decode or use MogT.FindChan for typed docs.
+func (mog *Mog) FindChan(criteria interface{}, sortFlds ...string) (<-chan bson.Raw, func() error) {
+	return findChan(mog, criteria, sortFlds, func(raw bson.Raw) (bson.Raw, error) {
+		doc := make(bson.Raw, len(raw)) // cursor reuses raw's memory
+		copy(doc, raw)
+		return doc, nil
+	})
+}
+
+// FindChan works same as Mog.FindChan except docs are decoded into type T, calling AfterFind if T implements AfterFinder.
+func (mt *MogT[T]) FindChan(criteria interface{}, sortFlds ...string) (<-chan T, func() error) {
+	return findChan(mt.Mog, criteria, sortFlds, func(raw bson.Raw) (T, error) {
+		var doc T
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return doc, err
+		}
+		if _, ok := any(doc).(AfterFinder); ok { // T is a pointer type
+			return doc, afterFind(doc)
+		}
+		return doc, afterFind(&doc)
+	})
+}
+
+// findChan runs the query in a goroutine, sending docs converted by decode to the returned channel.
+func findChan[T any](mog *Mog, criteria interface{}, sortFlds []string, decode func(bson.Raw) (T, error)) (<-chan T, func() error) {
+	findOptions := mog.findOptions(sortFlds)
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	criteria = mog.notDeleted(criteria)
+	ctx := mog.ctx
+	opener := mog.Clone() // mog may be used by the caller while the query runs
+	docs := make(chan T)
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		defer close(docs)
+		var cursor *mongo.Cursor
+		findErr := opener.timed(opener.readTimeout, func() error {
+			return opener.guard(func() error {
+				var err error
+				cursor, err = opener.collection.Find(opener.ctx, criteria, findOptions)
+				return err
+			})
+		})
+		if findErr != nil {
+			err = findErr
+			return
+		}
+		defer cursor.Close(context.Background()) // ctx may be cancelled
+		for cursor.Next(ctx) {
+			doc, decodeErr := decode(cursor.Current)
+			if decodeErr != nil {
+				err = decodeErr
+				return
+			}
+			select {
+			case docs <- doc:
+			case <-ctx.Done():
+				err = ctx.Err()
+				return
+			}
+		}
+		err = cursor.Err()
+	}()
+	return docs, func() error {
+		<-done
+		return err
+	}
+}
@@ -0,0 +1,79 @@
+package mog
+
+import (
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// --- GridFS Methods ----------------------------------------------------
+// Files are stored in the default GridFS bucket ("fs.files" and "fs.chunks" collections) of mog's database.
+
+// FileInfo describes a file stored in GridFS, see FileList.
+type FileInfo struct {
+	Id         interface{} `bson:"_id"`
+	Name       string      `bson:"filename"`
+	Length     int64       `bson:"length"`
+	ChunkSize  int32       `bson:"chunkSize"`
+	UploadDate time.Time   `bson:"uploadDate"`
+	Metadata   bson.M      `bson:"metadata"`
+}
+
+// FilePut stores the contents of r as a file named name. Parm "meta" is optional (nil) metadata saved with the file.
+// Returns the new file's id, use it to associate the file with a document.
+func (mog *Mog) FilePut(name string, r io.Reader, meta bson.M) (primitive.ObjectID, error) {
+	bucket, err := gridfs.NewBucket(mog.db)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	opts := options.GridFSUpload()
+	if meta != nil {
+		opts.SetMetadata(meta)
+	}
+	return bucket.UploadFromStream(name, r, opts)
+}
+
+// FileGet writes the contents of the file with matching id to w.
+// If no file has matching id, gridfs.ErrFileNotFound is returned.
+func (mog *Mog) FileGet(id interface{}, w io.Writer) error {
+	bucket, err := gridfs.NewBucket(mog.db)
+	if err != nil {
+		return err
+	}
+	_, err = bucket.DownloadToStream(id, w)
+	return err
+}
+
+// FileDelete deletes the file with matching id.
+// If no file has matching id, gridfs.ErrFileNotFound is returned.
+func (mog *Mog) FileDelete(id interface{}) error {
+	bucket, err := gridfs.NewBucket(mog.db)
+	if err != nil {
+		return err
+	}
+	return bucket.DeleteContext(mog.ctx, id)
+}
+
+// FileList returns info for files matching criteria (nil for all files), sorted by name.
+// Criteria uses the files collection field names, e.g. bson.M{"metadata.owner": "jay"}.
+func (mog *Mog) FileList(criteria interface{}) ([]FileInfo, error) {
+	bucket, err := gridfs.NewBucket(mog.db)
+	if err != nil {
+		return nil, err
+	}
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	opts := options.GridFSFind().SetSort(bson.D{{Key: "filename", Value: 1}})
+	cursor, err := bucket.FindContext(mog.ctx, criteria, opts)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]FileInfo, 0)
+	err = cursor.All(mog.ctx, &files)
+	return files, err
+}
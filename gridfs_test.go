@@ -0,0 +1,38 @@
+package mog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+)
+
+func Test_GridFS(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	mog1.db.Collection("fs.files").Drop(mog1.ctx)
+	mog1.db.Collection("fs.chunks").Drop(mog1.ctx)
+
+	id, err := mog1.FilePut("deed.txt", strings.NewReader("deed for 200 Willow Rd"), bson.M{"property_id": "p1"})
+	if err != nil {
+		t.Fatal("FilePut Failed", err)
+	}
+	var buf bytes.Buffer
+	if err = mog1.FileGet(id, &buf); err != nil || buf.String() != "deed for 200 Willow Rd" {
+		t.Fatal("FileGet Failed", err, buf.String())
+	}
+	files, err := mog1.FileList(bson.M{"metadata.property_id": "p1"})
+	if err != nil || len(files) != 1 || files[0].Name != "deed.txt" || files[0].Length != 22 {
+		t.Fatal("FileList Failed", err, files)
+	}
+	if err = mog1.FileDelete(id); err != nil {
+		t.Fatal("FileDelete Failed", err)
+	}
+	if err = mog1.FileGet(id, &buf); err != gridfs.ErrFileNotFound {
+		t.Fatal("FileGet After Delete Wrong Error", err)
+	}
+	fmt.Println("gridfs successful")
+}
@@ -0,0 +1,84 @@
+package mog
+
+import (
+	"reflect"
+)
+
+// --- Hook Methods ----------------------------------------------------
+
+// BeforeInserter is implemented by doc types needing work (defaults, validation, derived fields) before insert.
+// BeforeInsert is called by Insert for each doc, and by BulkAddInsert, BulkBatch.AddInsert, BulkWriter.Add and
+// BulkFromChannel. If it returns an error, no docs are inserted.
+// Pass docs by address when the method has a pointer receiver.
+type BeforeInserter interface {
+	BeforeInsert() error
+}
+
+// BeforeUpdater is implemented by doc types needing work before update.
+// BeforeUpdate is called for the new doc of Replace, Save, ReplaceVersioned, FindOneAndReplace and BulkAddReplace,
+// and for the update doc of Update, UpdateId and FindOneAndUpdate when its type implements it.
+// If it returns an error, nothing is written.
+type BeforeUpdater interface {
+	BeforeUpdate() error
+}
+
+// AfterFinder is implemented by doc types needing work after being loaded, such as populating derived fields.
+// AfterFind is called after a doc is decoded by Next, FindOne, FindId, FindOneAndUpdate, FindOneAndReplace and
+// MogT.FindChan, and for each doc loaded by FindAll, FindPage, Paginate, TextSearch, FindRandom, FindDeleted and AggRunAll.
+// Its error is returned (Next returns false and the error is available from IterErr).
+type AfterFinder interface {
+	AfterFind() error
+}
+
+// beforeInsert calls BeforeInsert for each doc implementing BeforeInserter.
+func beforeInsert(docs []interface{}) error {
+	for _, doc := range docs {
+		if hook, ok := doc.(BeforeInserter); ok {
+			if err := hook.BeforeInsert(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// beforeUpdate calls BeforeUpdate if doc implements BeforeUpdater.
+func beforeUpdate(doc interface{}) error {
+	if hook, ok := doc.(BeforeUpdater); ok {
+		return hook.BeforeUpdate()
+	}
+	return nil
+}
+
+// afterFind calls AfterFind if doc implements AfterFinder.
+func afterFind(doc interface{}) error {
+	if hook, ok := doc.(AfterFinder); ok {
+		return hook.AfterFind()
+	}
+	return nil
+}
+
+var afterFinderType = reflect.TypeOf((*AfterFinder)(nil)).Elem()
+
+// afterFindAll calls AfterFind for each doc in the slice docs points to, if the docs implement AfterFinder.
+func afterFindAll(docs interface{}) error {
+	slice := reflect.ValueOf(docs)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return nil
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	if elemType.Kind() != reflect.Interface && !reflect.PointerTo(elemType).Implements(afterFinderType) && !elemType.Implements(afterFinderType) {
+		return nil
+	}
+	for i := 0; i < slice.Len(); i++ {
+		doc := slice.Index(i)
+		if doc.Kind() != reflect.Ptr && doc.Kind() != reflect.Interface {
+			doc = doc.Addr()
+		}
+		if err := afterFind(doc.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package mog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type hookedProperty struct {
+	Id      string `bson:"_id"`
+	City    string `bson:"city"`
+	St      string `bson:"st"`
+	CitySt  string `bson:"-"`
+	Updated bool   `bson:"updated"`
+}
+
+func (p *hookedProperty) BeforeInsert() error {
+	if p.Id == "" {
+		return errors.New("id required")
+	}
+	p.St = strings.ToUpper(p.St)
+	return nil
+}
+
+func (p *hookedProperty) BeforeUpdate() error {
+	p.Updated = true
+	return nil
+}
+
+func (p *hookedProperty) AfterFind() error {
+	p.CitySt = p.City + " " + p.St
+	return nil
+}
+
+type hookedUpdate struct {
+	Set m `bson:"$set"`
+}
+
+func (u *hookedUpdate) BeforeUpdate() error {
+	u.Set["updated"] = true
+	return nil
+}
+
+func Test_Hooks(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	if err := mog1.Insert(&hookedProperty{Id: "p1", City: "Wonder", St: "mt"}); err != nil {
+		t.Fatal("Insert With Hook Failed", err)
+	}
+	if err := mog1.Insert(&hookedProperty{Id: "p2"}, &hookedProperty{City: "Wonder"}); err == nil {
+		t.Fatal("BeforeInsert Error Not Returned")
+	}
+	var prop hookedProperty
+	if err := mog1.FindId("p1", &prop); err != nil || prop.St != "MT" || prop.CitySt != "Wonder MT" {
+		t.Fatal("BeforeInsert Or AfterFind Not Called", err, prop)
+	}
+	prop.City = "Las Vegas"
+	if err := mog1.Replace(m{"_id": "p1"}, &prop); err != nil {
+		t.Fatal("Replace With Hook Failed", err)
+	}
+	var props []hookedProperty
+	if err := mog1.FindAll(nil, &props); err != nil || len(props) != 1 || !props[0].Updated || props[0].CitySt != "Las Vegas MT" {
+		t.Fatal("BeforeUpdate Or AfterFind Not Called", err, props)
+	}
+	if err := mog1.UpdateId("p1", &hookedUpdate{Set: m{"city": "Reno", "updated": false}}); err != nil {
+		t.Fatal("UpdateId With Hook Failed", err)
+	}
+	var page []hookedProperty
+	if _, err := mog1.FindPage(nil, 10, "", &page); err != nil || len(page) != 1 || !page[0].Updated || page[0].CitySt != "Reno MT" {
+		t.Fatal("UpdateId BeforeUpdate Or FindPage AfterFind Not Called", err, page)
+	}
+	docs, findErr := NewTypedMog[hookedProperty](mog1.ctx, mog1.db, "property").FindChan(nil)
+	for doc := range docs {
+		if doc.CitySt != "Reno MT" {
+			t.Fatal("FindChan AfterFind Not Called", doc)
+		}
+	}
+	if err := findErr(); err != nil {
+		t.Fatal("FindChan With Hook Failed", err)
+	}
+	var found hookedProperty
+	mog1.ReturnAfter()
+	if err := mog1.FindOneAndUpdate(m{"_id": "p1"}, &hookedUpdate{Set: m{"city": "Boise", "updated": false}}, &found); err != nil {
+		t.Fatal("FindOneAndUpdate With Hook Failed", err)
+	}
+	if !found.Updated || found.CitySt != "Boise MT" {
+		t.Fatal("FindOneAndUpdate BeforeUpdate Or AfterFind Not Called", found)
+	}
+	batch := mog1.NewBulkBatch(2)
+	batch.AddInsert(&hookedProperty{Id: "p2"})
+	batch.AddInsert(&hookedProperty{City: "Wonder"})
+	if _, err := batch.Commit(); err == nil {
+		t.Fatal("BulkBatch BeforeInsert Error Not Returned")
+	}
+	if count, err := mog1.Count(nil); err != nil || count != 1 {
+		t.Fatal("BulkBatch Wrote After BeforeInsert Error", err, count)
+	}
+	fmt.Println("hooks successful")
+}
@@ -0,0 +1,260 @@
+package mog
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// --- Index Methods ----------------------------------------------------
+
+// IndexOpts are the options used by CreateIndex. The zero value creates a plain index with a generated name.
+type IndexOpts struct {
+	Name               string // default generated from keys, e.g. "city_1_st_-1"
+	Unique             bool   // reject docs with duplicate key values
+	Sparse             bool   // only index docs containing the key fields
+	ExpireAfterSeconds int32  // if > 0, docs are removed this many seconds after the date in the key field (TTL index)
+}
+
+// IndexInfo describes an index, see ListIndexes.
+type IndexInfo struct {
+	Name               string `bson:"name"`
+	Keys               bson.D `bson:"key"`
+	Unique             bool   `bson:"unique"`
+	Sparse             bool   `bson:"sparse"`
+	ExpireAfterSeconds *int32 `bson:"expireAfterSeconds"` // nil if not a TTL index
+}
+
+// CreateIndex creates an index on the collection and returns its name.
+// Parm "keys" lists the key fields in order, value 1 for ascending, -1 for descending.
+// Ex: CreateIndex(bson.D{{Key: "city", Value: 1}, {Key: "st", Value: 1}}, IndexOpts{Unique: true})
+// Creating an index that already exists with the same options does nothing.
+func (mog *Mog) CreateIndex(keys bson.D, opts IndexOpts) (string, error) {
+	indexOptions := options.Index()
+	if opts.Name != "" {
+		indexOptions.SetName(opts.Name)
+	}
+	if opts.Unique {
+		indexOptions.SetUnique(true)
+	}
+	if opts.Sparse {
+		indexOptions.SetSparse(true)
+	}
+	if opts.ExpireAfterSeconds > 0 {
+		indexOptions.SetExpireAfterSeconds(opts.ExpireAfterSeconds)
+	}
+	model := mongo.IndexModel{Keys: keys, Options: indexOptions}
+	return mog.collection.Indexes().CreateOne(mog.ctx, model)
+}
+
+// ListIndexes returns info for all indexes on the collection, including the default _id index.
+func (mog *Mog) ListIndexes() ([]IndexInfo, error) {
+	cursor, err := mog.collection.Indexes().List(mog.ctx)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []IndexInfo
+	err = cursor.All(mog.ctx, &indexes)
+	return indexes, err
+}
+
+// DropIndex drops the index named name.
+func (mog *Mog) DropIndex(name string) error {
+	_, err := mog.collection.Indexes().DropOne(mog.ctx, name)
+	return err
+}
+
+// CreateTTLIndex creates an index on date field that removes docs once expireAfter has passed since the field's date.
+// Returns the index name. Useful for session and cache style collections.
+// Durations are rounded down to whole seconds, minimum is 1 second.
+func (mog *Mog) CreateTTLIndex(field string, expireAfter time.Duration) (string, error) {
+	seconds := int32(expireAfter / time.Second)
+	if seconds < 1 {
+		return "", errors.New("TTL expireAfter must be at least 1 second")
+	}
+	return mog.CreateIndex(bson.D{{Key: field, Value: 1}}, IndexOpts{ExpireAfterSeconds: seconds})
+}
+
+// UpdateTTLIndex changes the expiration of the existing TTL index on field (created by CreateTTLIndex),
+// without rebuilding the index.
+func (mog *Mog) UpdateTTLIndex(field string, expireAfter time.Duration) error {
+	seconds := int32(expireAfter / time.Second)
+	if seconds < 1 {
+		return errors.New("TTL expireAfter must be at least 1 second")
+	}
+	cmd := bson.D{
+		{Key: "collMod", Value: mog.collectionName},
+		{Key: "index", Value: bson.D{
+			{Key: "keyPattern", Value: bson.D{{Key: field, Value: 1}}},
+			{Key: "expireAfterSeconds", Value: seconds},
+		}},
+	}
+	return mog.db.RunCommand(mog.ctx, cmd).Err()
+}
+
+// EnsureIndexes creates the indexes defined by mogIndex struct tags of model (struct or pointer to struct).
+// Indexes that already exist are left as is, so EnsureIndexes can be run at every startup.
+// A tag holds 1 or more specs separated by ";", key field names are the bson names:
+//
+//	index                 - ascending index on the field
+//	unique                - unique index on the field
+//	desc                  - makes field index descending
+//	sparse                - makes field index sparse
+//	ttl:seconds           - TTL index on the field (date field), docs expire after seconds
+//	compound:fld1,-fld2   - compound index on listed fields, "-" prefix for descending
+//	uniqueCompound:fld1,fld2 - unique compound index
+//
+// Ex: City string `bson:"city" mogIndex:"index;compound:city,st"`
+func (mog *Mog) EnsureIndexes(model interface{}) error {
+	modelType := reflect.TypeOf(model)
+	if modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return errors.New("EnsureIndexes model must be a struct")
+	}
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		tag, found := field.Tag.Lookup("mogIndex")
+		if !found {
+			continue
+		}
+		fldName := strings.Split(field.Tag.Get("bson"), ",")[0]
+		if fldName == "" {
+			fldName = strings.ToLower(field.Name)
+		}
+		fldIndex := false
+		fldOrder := 1
+		var fldOpts IndexOpts
+		for _, spec := range strings.Split(tag, ";") {
+			spec = strings.TrimSpace(spec)
+			name, value, _ := strings.Cut(spec, ":")
+			switch name {
+			case "index":
+				fldIndex = true
+			case "unique":
+				fldIndex = true
+				fldOpts.Unique = true
+			case "desc":
+				fldIndex = true
+				fldOrder = -1
+			case "sparse":
+				fldIndex = true
+				fldOpts.Sparse = true
+			case "ttl":
+				seconds, err := strconv.Atoi(value)
+				if err != nil || seconds <= 0 {
+					return errors.New("invalid mogIndex ttl on " + field.Name + ": " + spec)
+				}
+				fldIndex = true
+				fldOpts.ExpireAfterSeconds = int32(seconds)
+			case "compound", "uniqueCompound":
+				if value == "" {
+					return errors.New("mogIndex compound has no fields on " + field.Name)
+				}
+				keyFlds := strings.Split(value, ",")
+				for i, keyFld := range keyFlds {
+					keyFlds[i] = strings.TrimSpace(keyFld)
+					if strings.TrimPrefix(keyFlds[i], "-") == "" {
+						return errors.New("mogIndex compound has empty field on " + field.Name + ": " + spec)
+					}
+				}
+				keys := CreateSortOrder(keyFlds)
+				if _, err := mog.CreateIndex(keys, IndexOpts{Unique: name == "uniqueCompound"}); err != nil {
+					return err
+				}
+			default:
+				return errors.New("invalid mogIndex spec on " + field.Name + ": " + spec)
+			}
+		}
+		if fldIndex {
+			keys := bson.D{{Key: fldName, Value: fldOrder}}
+			if _, err := mog.CreateIndex(keys, fldOpts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WarnIfUnindexed returns an error if no index on the collection can support a query using criteria.
+// An index can support the query if its leading field is one of the criteria fields.
+// Intended for use during development to catch queries that scan the whole collection.
+// Returns nil for nil or empty criteria.
+func (mog *Mog) WarnIfUnindexed(criteria interface{}) error {
+	if criteria == nil {
+		return nil
+	}
+	flds, err := criteriaFields(criteria)
+	if err != nil || len(flds) == 0 {
+		return err
+	}
+	fldSet := make(map[string]bool)
+	for _, fld := range flds {
+		fldSet[fld] = true
+	}
+	cursor, err := mog.collection.Indexes().List(mog.ctx)
+	if err != nil {
+		return err
+	}
+	var indexes []struct {
+		Key bson.D `bson:"key"`
+	}
+	if err = cursor.All(mog.ctx, &indexes); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if len(index.Key) > 0 && fldSet[index.Key[0].Key] {
+			return nil
+		}
+	}
+	return errors.New("no index supports query on " + mog.collectionName + " fields: " + strings.Join(flds, ", "))
+}
+
+// criteriaFields returns the field names used in criteria.
+// Fields inside top level $and/$or/$nor clauses are included, other operators are ignored.
+func criteriaFields(criteria interface{}) ([]string, error) {
+	raw, err := bson.Marshal(criteria)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := bson.Raw(raw).Elements()
+	if err != nil {
+		return nil, err
+	}
+	var flds []string
+	for _, element := range elements {
+		key := element.Key()
+		if !strings.HasPrefix(key, "$") {
+			flds = append(flds, key)
+			continue
+		}
+		if key != "$and" && key != "$or" && key != "$nor" {
+			continue
+		}
+		clauses, ok := element.Value().ArrayOK()
+		if !ok {
+			continue
+		}
+		values, err := clauses.Values()
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			if clause, ok := value.DocumentOK(); ok {
+				clauseFlds, err := criteriaFields(clause)
+				if err != nil {
+					return nil, err
+				}
+				flds = append(flds, clauseFlds...)
+			}
+		}
+	}
+	return flds, nil
+}
@@ -0,0 +1,134 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Test_WarnIfUnindexed(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	criteria := m{"city": "Wonder"}
+	if err := mog1.WarnIfUnindexed(criteria); err == nil {
+		t.Fatal("WarnIfUnindexed Did Not Warn")
+	}
+	model := mongo.IndexModel{Keys: bson.D{{Key: "city", Value: 1}, {Key: "st", Value: 1}}}
+	if _, err := mog1.collection.Indexes().CreateOne(mog1.ctx, model); err != nil {
+		t.Fatal("Create Index Failed", err)
+	}
+	if err := mog1.WarnIfUnindexed(criteria); err != nil {
+		t.Fatal("WarnIfUnindexed Warned With Index", err)
+	}
+	fmt.Println("warnIfUnindexed successful")
+}
+
+func Test_IndexManagement(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	name, err := mog1.CreateIndex(bson.D{{Key: "address", Value: 1}}, IndexOpts{Name: "address_uniq", Unique: true, Sparse: true})
+	if err != nil || name != "address_uniq" {
+		t.Fatal("CreateIndex Failed", err, name)
+	}
+	if _, err = mog1.CreateIndex(bson.D{{Key: "date_added", Value: -1}}, IndexOpts{ExpireAfterSeconds: 3600}); err != nil {
+		t.Fatal("CreateIndex TTL Failed", err)
+	}
+	if err = mog1.Insert(Property{Id: "dup", Address: "200 Willow Rd"}); !mongo.IsDuplicateKeyError(err) {
+		t.Fatal("Unique Index Not Enforced", err)
+	}
+	indexes, err := mog1.ListIndexes()
+	if err != nil || len(indexes) != 3 {
+		t.Fatal("ListIndexes Failed", err, indexes)
+	}
+	for _, index := range indexes {
+		if index.Name == "address_uniq" && (!index.Unique || !index.Sparse) {
+			t.Fatal("ListIndexes Wrong Options", index)
+		}
+		if index.Name == "date_added_-1" && (index.ExpireAfterSeconds == nil || *index.ExpireAfterSeconds != 3600) {
+			t.Fatal("ListIndexes Wrong TTL", index)
+		}
+	}
+	if err = mog1.DropIndex("address_uniq"); err != nil {
+		t.Fatal("DropIndex Failed", err)
+	}
+	indexes, _ = mog1.ListIndexes()
+	if len(indexes) != 2 {
+		t.Fatal("DropIndex Did Not Drop", indexes)
+	}
+	fmt.Println("index management successful")
+}
+
+func Test_EnsureIndexes(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	type indexedProperty struct {
+		Id        string    `bson:"_id"`
+		Address   string    `bson:"address" mogIndex:"unique"`
+		City      string    `bson:"city" mogIndex:"index;compound:city, -st"`
+		St        string    `bson:"st"`
+		UpdatedAt time.Time `bson:"updated_at" mogIndex:"ttl:86400"`
+	}
+	for i := 0; i < 2; i++ { // 2nd run must not fail or add indexes
+		if err := mog1.EnsureIndexes(indexedProperty{}); err != nil {
+			t.Fatal("EnsureIndexes Failed", err)
+		}
+	}
+	indexes, _ := mog1.ListIndexes()
+	names := make(map[string]bool)
+	for _, index := range indexes {
+		names[index.Name] = true
+	}
+	for _, name := range []string{"_id_", "address_1", "city_1", "city_1_st_-1", "updated_at_1"} {
+		if !names[name] {
+			t.Fatal("EnsureIndexes Missing Index", name, names)
+		}
+	}
+	if len(indexes) != 5 {
+		t.Fatal("EnsureIndexes Wrong Index Count", names)
+	}
+	type badTag struct {
+		City string `bson:"city" mogIndex:"bogus"`
+	}
+	if err := mog1.EnsureIndexes(&badTag{}); err == nil {
+		t.Fatal("EnsureIndexes Bad Tag Did Not Fail")
+	}
+	type emptyCompoundFld struct {
+		City string `bson:"city" mogIndex:"compound:city,"`
+	}
+	if err := mog1.EnsureIndexes(&emptyCompoundFld{}); err == nil {
+		t.Fatal("EnsureIndexes Empty Compound Field Did Not Fail")
+	}
+	fmt.Println("ensureIndexes successful")
+}
+
+func Test_TTLIndex(t *testing.T) {
+	mog1, disconnect := testMog(t, "session")
+	defer disconnect()
+	mog1.Insert(m{"_id": "s1", "last_used": time.Now()})
+
+	if _, err := mog1.CreateTTLIndex("last_used", time.Hour); err != nil {
+		t.Fatal("CreateTTLIndex Failed", err)
+	}
+	if err := mog1.UpdateTTLIndex("last_used", 2*time.Hour); err != nil {
+		t.Fatal("UpdateTTLIndex Failed", err)
+	}
+	indexes, _ := mog1.ListIndexes()
+	for _, index := range indexes {
+		if index.Name == "last_used_1" && (index.ExpireAfterSeconds == nil || *index.ExpireAfterSeconds != 7200) {
+			t.Fatal("UpdateTTLIndex Did Not Update", index)
+		}
+	}
+	if _, err := mog1.CreateTTLIndex("last_used", time.Millisecond); err == nil {
+		t.Fatal("CreateTTLIndex Below 1 Second Did Not Fail")
+	}
+	fmt.Println("ttl index successful")
+}
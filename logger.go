@@ -0,0 +1,50 @@
+package mog
+
+import (
+	"log"
+)
+
+// Logger receives Mog's internal log messages, see SetLogger.
+// Implement it to route messages to a structured logging library.
+type Logger interface {
+	Printf(format string, args ...interface{})
+	Error(msg string, err error)
+}
+
+// noopLogger discards all messages. It is used when no logger is set.
+type noopLogger struct{}
+
+func (noopLogger) Printf(format string, args ...interface{}) {}
+func (noopLogger) Error(msg string, err error)               {}
+
+// stdLogger adapts a standard library *log.Logger to Logger.
+type stdLogger struct {
+	l *log.Logger
+}
+
+// NewStdLogger returns a Logger writing to l. Use log.Default() for the standard logger.
+func NewStdLogger(l *log.Logger) Logger {
+	return stdLogger{l: l}
+}
+
+func (s stdLogger) Printf(format string, args ...interface{}) {
+	s.l.Printf(format, args...)
+}
+
+func (s stdLogger) Error(msg string, err error) {
+	s.l.Println(msg, err)
+}
+
+// SetLogger sets the Logger used for Mog's internal log messages, such as decode errors in Next.
+// Default discards all messages (except DryRun messages, written to the standard logger). Pass nil to restore the default.
+func (mog *Mog) SetLogger(l Logger) {
+	mog.logger = l
+}
+
+// log returns the Logger set by SetLogger or a no-op Logger.
+func (mog *Mog) log() Logger {
+	if mog.logger == nil {
+		return noopLogger{}
+	}
+	return mog.logger
+}
@@ -2,65 +2,163 @@
 package mog
 
 // mog := NewMog(db, ...collectionName)  	// db is *mongo.Database, collectionName is optional
+// mog, disconnect, err := NewMogFromURI(ctx, uri, dbName, ...collectionName) // connect, ping and create Mog
+// mog.WithContext(ctx)					// Clone using ctx for operations, for per-request deadlines and cancellation
 // mog.SetCollection(collectionName)		// change collection
+// mog.SetDatabase(dbName)					// change database on same client, current collection name kept
+// mog.Clone()								// copy of mog for use in another goroutine, settings shared, state not
+// mog.SetReadPreference(mode)				// route reads to primary, secondary, nearest, etc.
+// mog.SetWriteConcern(w, journal, timeout)	// acknowledgment required for writes, w is count or "majority"
+// mog.SetReadConcern(level)				// consistency of data read: local, majority, snapshot, etc.
 // mog.SetLimit(limit int64)					// set limit value, resets after execution
+// mog.SetSkip(n int64)						// skip 1st n results, resets after execution
+// mog.SetBatchSize(n int32)				// docs per cursor round trip, resets after execution
+// mog.SetHint(index)						// force index for next query or update, resets after execution
+// mog.SetCollation(locale, caseInsensitive) // language rules for next query or update, resets after execution
+// mog.SetMaxTime(d time.Duration)			// server time limit for next query or aggregation, resets after execution
 // mog.KeepFlds(fld1, fld2, ...)  			// specify flds to return in Find results
 // mog.OmitFlds(fld1, fld2, ...)  			// specify flds to omit from Find results
-// mog.Find(criteria, ...sortFlds)  		// creates iterator (cursor), sortFlds optional, nil criteria returns all docs
+// mog.Find(criteria, ...sortFlds)  		// creates iterator (cursor), sortFlds optional, nil criteria returns all docs, returns error
 // mog.Next(&doc)  							// use after Find, loads target with next doc from results, iter closed automatically at end, returns true if more
 // mog.FindAll(criteria, docs, ...sortFlds) // works same as Find(), except all results are loaded into docs slice
+// mog.FindRandom(n, docs)					// loads docs with n randomly selected docs
+// mog.FindUntil(criteria, fn, ...sortFlds) // calls fn for each doc until fn returns stop, cursor always closed
+// mog.FindChan(criteria, ...sortFlds)		// returns channel of docs and func returning final error
 // mog.IterErr() error						// returns iterator (cursor) error after completing Find/Next process
 // mog.FindOne(criteria, &doc, ...sortFlds) // loads doc with 1st result, sortFlds optionals
 // mog.FindId(docId, &doc) 					// loads doc with result having matching id
+// mog.TextSearch(phrase, docs)			// loads docs matching phrase using text index, most relevant first
 // mog.Count(criteria) 						// returns count of docs matching criteria
+// mog.ExplainFind(criteria, verbosity, ...sortFlds) // returns query plan: indexes used, docs examined, etc.
+// mog.AggExplain()							// returns query plan and per stage stats of AggPipeline
+// mog.Exists(criteria)						// returns true if any doc matches criteria
+// mog.Distinct(fieldName, criteria) 		// returns distinct values of field in docs matching criteria
+// mog.DistinctInto(fieldName, criteria, &values) // loads distinct values into typed slice
 // mog.Update(criteria, update)  			// update all docs matching criteria using update object
 // mog.Replace(criteria, newDoc)  			// replace 1st doc matching criteria with newDoc
+// mog.UpdateVersioned(&doc, update)		// update doc if version (field tagged mog:"version") unchanged, else ErrVersionConflict
+// mog.ReplaceVersioned(&doc)				// replace doc if version unchanged, else ErrVersionConflict
+// mog.Save(doc)							// replace doc with same _id, insert if not found
 // mog.Upsert()								// turn upsert option on for updates, resets after execution
+// mog.FindOneAndUpdate(criteria, update, &doc, ...sortFlds) // atomically update 1st doc, load doc before (or after) update
+// mog.FindOneAndReplace(criteria, newDoc, &oldDoc, ...sortFlds) // atomically replace 1st doc, load replaced doc
+// mog.FindOneAndDelete(criteria, &doc, ...sortFlds) // atomically delete 1st doc, load deleted doc
+// mog.ReturnAfter()						// FindOneAnd.. methods return doc after modification, resets after execution
 // mog.Insert(doc1, doc2, ...)  			// insert 1 or more docs
+// mog.DeleteOne(criteria)					// delete 1st doc matching criteria
+// mog.DeleteMany(criteria)					// delete all docs matching criteria
+// mog.DeleteManyChunked(criteria, chunkSize, pause, ...progress) // delete matching docs in chunks, pausing between chunks
+// mog.DeleteId(docId)						// delete doc with matching id
+// mog.Deduplicate(keyFld1, keyFld2, ...)	// remove docs with duplicate key values, keeps lowest _id
 // mog.BulkStart(size int)					// start bulk process, size is estimated count of inserts + updates
 // mog.BulkAddInsert(doc interface{}) 		// append doc to be inserted to mog.BulkWrites slice
 // mog.BulkAddUpdate(criteria, update interface{}) // append criteria and update code to mog.BulkWrites slice
-// mog.BulkWrite()							// apply inserts/updates stored in mog.BulkWrites, returns total of inserts + updates
+// mog.BulkAddUpdateOne(criteria, update, upsert) // append update of 1st matching doc to mog.BulkWrites slice
+// mog.BulkAddReplace(criteria, newDoc, upsert) // append replacement of 1st matching doc to mog.BulkWrites slice
+// mog.BulkAddDelete(criteria)				// append criteria of docs to be deleted to mog.BulkWrites slice
+// mog.BulkAddDeleteOne(criteria)			// append criteria of 1 doc to be deleted to mog.BulkWrites slice
+// mog.BulkUnordered()						// next BulkWrite unordered, continues after failed writes
+// mog.BulkAutoFlush(threshold)			// BulkAdd.. methods write pending entries when threshold reached
+// mog.BulkErr()						// error of failed auto flush, BulkAdd.. entries dropped once set
+// mog.BulkWrite()							// apply writes stored in mog.BulkWrites, returns total of inserts + updates + deletes
+// mog.BulkFromChannel(ch, batchSize)		// insert docs received from ch in bulk writes until ch closed
+// mog.BulkWriteDetailed()					// same as BulkWrite, returns counts by type of write, upserted ids, failed writes
+// mog.WithTransaction(fn)					// run fn(txMog) in transaction, commit or abort based on error returned
+// mog.WatchStart(...pipeline)				// open change stream on collection
+// mog.WatchNext(&event)					// wait for next change event, returns false when stream ends
+// mog.WatchClose()							// close change stream
 // mog.CsvOutStart(filePath)				// begin csv output
 // mog.CsvWrite(record)						// write record to csv output
 // mog.CsvOutDone()							// complete csv output
 // mog.CsvInStart(filePath)					// begin csv input
 // mog.CsvRead()							// read record from csv input
 // mog.CsvInDone()							// close csv input file
+// mog.SetLogger(l Logger)					// route internal log messages, default discards them
+// mog.SetRetries(n int)					// retry failed reads/writes up to n times when error is retryable
+// mog.SetRetryClassifier(fn)				// customize which errors are retryable
+// mog.SetWriteRateLimit(opsPerSecond)		// pace Insert/Update/BulkWrite, 0 removes limit
+// BeforeInserter, BeforeUpdater, AfterFinder // hook interfaces, methods called if implemented by doc types
+// mog.AutoTimestamps(on bool)				// Insert/Update/Replace/Bulk.. set created_at and updated_at fields
+// mog.EnableSoftDelete(field)				// deletes set field instead of removing docs, reads skip them
+// mog.Restore(criteria)					// undelete soft deleted docs matching criteria
+// mog.FindDeleted(criteria, docs, ...sortFlds) // load soft deleted docs matching criteria
+// mog.EnableAudit(&opts)					// record writes (criteria, change, before-image, actor, time) in <collection>_audit
+// mog.SetDefaultTimeout(read, write)		// time limit for each read and write operation, 0 for none
+// mog.SetCircuitBreaker(threshold, coolDown) // fail fast with ErrCircuitOpen after threshold consecutive failures
+// mog.DryRun(on bool)						// log all writes instead of writing
 
 import (
 	"context"
 	"encoding/csv"
 	"errors"
 	"fmt"
-	"log"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // Type Mog contains almost everything.
+// A Mog is not safe for concurrent use, it holds per-operation state (limit, upsert, iterator, etc.).
+// Use Clone to derive a Mog for each goroutine (e.g. each web request).
 type Mog struct {
 	ctx             context.Context
 	db              *mongo.Database
 	collection      *mongo.Collection
 	collectionName  string
-	projectFlds     bson.M             // flds to be kept or omitted, use .KeepFlds or .OmitFlds to load
-	bulkWrites      []mongo.WriteModel // Used by BulK.. methods
+	readPref        *readpref.ReadPref         // applied to collection handle, see SetReadPreference
+	writeConcern    *writeconcern.WriteConcern // applied to collection handle, see SetWriteConcern
+	readConcern     *readconcern.ReadConcern   // applied to collection handle, see SetReadConcern
+	projectFlds     bson.M                     // flds to be kept or omitted, use .KeepFlds or .OmitFlds to load
+	bulkWrites      []mongo.WriteModel         // Used by BulK.. methods
 	iter            *mongo.Cursor
 	iterErr         error
+	stream          *mongo.ChangeStream // used by Watch.. methods
+	streamErr       error
+	tokenStore      ResumeTokenStore // see SetResumeTokenStore
+	streamName      string
 	limit           int64
-	upsert          bool // if true, Update will add docs not matching criteria
+	skip            int64
+	batchSize       int32
+	hint            interface{} // index name or key spec, see SetHint
+	collation       *options.Collation
+	maxTime         time.Duration
+	upsert          bool            // if true, Update will add docs not matching criteria
+	returnAfter     bool            // if true, FindOneAnd.. methods return doc after modification
+	bulkUnordered   bool            // if true, next BulkWrite continues after failed writes
+	bulkFlushAt     int             // if > 0, BulkAdd.. methods write pending entries when this many, see BulkAutoFlush
+	bulkFlushResult BulkWriteResult // result of auto flushes since last BulkWrite
+	bulkFlushed     int             // number of entries written by auto flushes since last BulkWrite
+	bulkFlushErr    error           // error of failed auto flush, returned by BulkWrite
+	allowDiskUse    bool            // if true, next Agg.. run may use temp files for large sorts/groups
+	dryRun          bool            // if true, writes are logged instead of executed, see DryRun
+	autoTimestamps  bool            // if true, writes set created_at/updated_at, see AutoTimestamps
+	softDeleteFld   string          // if not "", field set by deletes instead of removing docs, see EnableSoftDelete
+	auditOpts       *AuditOpts      // if not nil, writes are recorded in audit collection, see EnableAudit
 	csvFile         *os.File
 	csvWriter       *csv.Writer
 	csvReader       *csv.Reader
 	CsvHeaders      map[int]string
 	CsvHeadersIndex map[string]int
 	AggPipeline     []bson.M
+	AggParams       bson.M           // params of current AggRunNamed, read by pipeline build func
+	retries         int              // number of times a failed read or write is retried, see SetRetries
+	retryClassifier func(error) bool // decides if error is retryable, see SetRetryClassifier
+	writeLimiter    *rateLimiter     // paces writes, see SetWriteRateLimit
+	breaker         *circuitBreaker  // fails fast when server unavailable, see SetCircuitBreaker
+	readTimeout     time.Duration    // time limit of each read, see SetDefaultTimeout
+	writeTimeout    time.Duration    // time limit of each write, see SetDefaultTimeout
+	logger          Logger           // see SetLogger
 }
 
 // NewMog creates instance of Mog.
@@ -76,27 +174,328 @@ func NewMog(ctx context.Context, db *mongo.Database, collectionName ...string) *
 	return &mog
 }
 
+// NewMogFromURI connects to the server at uri, verifies the connection, and creates instance of Mog using db dbName.
+// The returned func disconnects from the server, typically called using defer.
+// Ex: mog1, disconnect, err := NewMogFromURI(ctx, "mongodb://localhost:27017", "demo", "property")
+func NewMogFromURI(ctx context.Context, uri, dbName string, collectionName ...string) (*Mog, func(), error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, nil, err
+	}
+	disconnect := func() { client.Disconnect(ctx) }
+	if err = client.Ping(ctx, readpref.Primary()); err != nil {
+		disconnect()
+		return nil, nil, err
+	}
+	return NewMog(ctx, client.Database(dbName), collectionName...), disconnect, nil
+}
+
+// Clone returns a new Mog sharing mog's database, collection and settings (read/write concerns,
+// read preference, retries, timeouts, write rate limit, circuit breaker, logger, dry run, auto timestamps,
+// soft delete, audit, Keep/Omit fields, AggPipeline).
+// Per-operation state (limit, skip, upsert, iterator, bulk writes, csv files, etc.) is not copied.
+// Clones are cheap, use one per goroutine. The write rate limit and circuit breaker are shared by mog and its clones.
+func (mog *Mog) Clone() *Mog {
+	clone := &Mog{
+		ctx:             mog.ctx,
+		db:              mog.db,
+		collection:      mog.collection,
+		collectionName:  mog.collectionName,
+		readPref:        mog.readPref,
+		writeConcern:    mog.writeConcern,
+		readConcern:     mog.readConcern,
+		retries:         mog.retries,
+		retryClassifier: mog.retryClassifier,
+		writeLimiter:    mog.writeLimiter,
+		breaker:         mog.breaker,
+		readTimeout:     mog.readTimeout,
+		writeTimeout:    mog.writeTimeout,
+		logger:          mog.logger,
+		bulkFlushAt:     mog.bulkFlushAt,
+		dryRun:          mog.dryRun,
+		autoTimestamps:  mog.autoTimestamps,
+		softDeleteFld:   mog.softDeleteFld,
+		auditOpts:       mog.auditOpts,
+	}
+	if mog.projectFlds != nil {
+		clone.projectFlds = make(bson.M, len(mog.projectFlds))
+		for fld, val := range mog.projectFlds {
+			clone.projectFlds[fld] = val
+		}
+	}
+	if mog.AggPipeline != nil {
+		clone.AggPipeline = append(make([]bson.M, 0, len(mog.AggPipeline)), mog.AggPipeline...)
+	}
+	return clone
+}
+
+// WithContext returns a Clone of mog using ctx for all operations, so individual operations
+// (e.g. those of a web request) can carry their own deadline and cancellation. mog is not changed.
+// Ex: err := mog.WithContext(r.Context()).FindAll(criteria, &props)
+func (mog *Mog) WithContext(ctx context.Context) *Mog {
+	clone := mog.Clone()
+	clone.ctx = ctx
+	return clone
+}
+
 // SetCollection changes the collection used.
 func (mog *Mog) SetCollection(collectionName string) {
-	mog.collection = mog.db.Collection(collectionName)
+	mog.collection = mog.db.Collection(collectionName, mog.collectionOptions())
 	mog.collectionName = collectionName
 }
 
+// SetDatabase changes the database used, keeping the same client.
+// The current collection name (if any) is used in the new database.
+// Returns error if mog was created without a database (nil db), as there is no client to use.
+func (mog *Mog) SetDatabase(dbName string) error {
+	if mog.db == nil {
+		return errors.New("mog has no database, cannot change database")
+	}
+	mog.db = mog.db.Client().Database(dbName)
+	if mog.collection != nil {
+		mog.SetCollection(mog.collectionName)
+	}
+	return nil
+}
+
+// collectionOptions returns options for collection handles using settings made by
+// SetReadPreference, SetWriteConcern and SetReadConcern.
+func (mog *Mog) collectionOptions() *options.CollectionOptions {
+	collectionOptions := options.Collection()
+	if mog.readPref != nil {
+		collectionOptions.SetReadPreference(mog.readPref)
+	}
+	if mog.writeConcern != nil {
+		collectionOptions.SetWriteConcern(mog.writeConcern)
+	}
+	if mog.readConcern != nil {
+		collectionOptions.SetReadConcern(mog.readConcern)
+	}
+	return collectionOptions
+}
+
+// SetReadPreference routes all subsequent reads to servers of the replica set matching mode.
+// Parm "mode" is one of primary, primaryPreferred, secondary, secondaryPreferred or nearest.
+// Applies to the current collection and collections set later by SetCollection.
+func (mog *Mog) SetReadPreference(mode string) error {
+	readMode, err := readpref.ModeFromString(mode)
+	if err != nil {
+		return err
+	}
+	mog.readPref, err = readpref.New(readMode)
+	if err != nil {
+		return err
+	}
+	if mog.collection != nil {
+		mog.SetCollection(mog.collectionName)
+	}
+	return nil
+}
+
+// SetWriteConcern sets the acknowledgment required for all subsequent writes.
+// Parm "w" is the number of members that must acknowledge (0 for none) or "majority".
+// If journal is true, writes must be committed to the on-disk journal. Parm "timeout" limits the wait (0 for none).
+// Applies to the current collection and collections set later by SetCollection.
+func (mog *Mog) SetWriteConcern(w interface{}, journal bool, timeout time.Duration) error {
+	switch w.(type) {
+	case int, string:
+	default:
+		return errors.New("write concern w must be int or string")
+	}
+	mog.writeConcern = &writeconcern.WriteConcern{W: w, WTimeout: timeout}
+	if journal {
+		mog.writeConcern.Journal = &journal
+	}
+	if mog.collection != nil {
+		mog.SetCollection(mog.collectionName)
+	}
+	return nil
+}
+
+// SetReadConcern sets the consistency of data returned by all subsequent reads (Find, Count, Agg.. runs).
+// Parm "level" is one of local, available, majority, snapshot or linearizable.
+// Applies to the current collection and collections set later by SetCollection.
+func (mog *Mog) SetReadConcern(level string) error {
+	switch level {
+	case "local", "available", "majority", "snapshot", "linearizable":
+	default:
+		return errors.New("invalid read concern level: " + level)
+	}
+	mog.readConcern = &readconcern.ReadConcern{Level: level}
+	if mog.collection != nil {
+		mog.SetCollection(mog.collectionName)
+	}
+	return nil
+}
+
 // SetLimit limits the number of docs returned. Resets after execution.
 func (mog *Mog) SetLimit(limit int64) {
 	mog.limit = limit
 }
 
+// SetSkip skips the 1st n docs of the next Find, FindAll, FindUntil or FindOne. Resets after execution.
+// Use with SetLimit for simple offset pagination.
+func (mog *Mog) SetSkip(n int64) {
+	mog.skip = n
+}
+
+// SetBatchSize sets the number of docs returned per round trip by the next Find, FindAll, FindUntil or Agg.. run.
+// Larger batches reduce round trips on big scans. Resets after execution.
+func (mog *Mog) SetBatchSize(n int32) {
+	mog.batchSize = n
+}
+
+// SetHint forces the next Find, FindAll, FindUntil, FindOne, Count or Update to use an index.
+// Parm "index" is the index name or its key spec (e.g. bson.D{{Key: "city", Value: 1}}). Resets after execution.
+func (mog *Mog) SetHint(index interface{}) {
+	mog.hint = index
+}
+
+// SetCollation sets the collation (language rules) used by the next Find, FindAll, FindUntil, FindOne, Count or Update.
+// Parm "locale" is an ICU locale such as "en" or "fr". If caseInsensitive is true, matching and sorting ignore case.
+// Resets after execution.
+func (mog *Mog) SetCollation(locale string, caseInsensitive bool) {
+	mog.collation = &options.Collation{Locale: locale}
+	if caseInsensitive {
+		mog.collation.Strength = 2 // compare base characters and accents, not case
+	}
+}
+
+// SetMaxTime limits the server execution time of the next Find, FindAll, FindUntil, FindOne, Count or Agg.. run.
+// If exceeded, the operation fails with a time limit error. Resets after execution.
+func (mog *Mog) SetMaxTime(d time.Duration) {
+	mog.maxTime = d
+}
+
 // Upsert turns upsert option on (see MongoDB doc). Resets after execution.
 func (mog *Mog) Upsert() {
 	mog.upsert = true
 }
 
-// Find sets mog.iter = mongo cursor (iterator) for docs meeting criteria.
-// Next() method uses mog.iter to iterate thru results.
-// Use criteria parm to filter results (nil for all docs in collection).
-// Use optional sortFlds to sort. Begin fieldname with "-" for descending.
-func (mog *Mog) Find(criteria interface{}, sortFlds ...string) {
+// ReturnAfter makes FindOneAndUpdate and FindOneAndReplace return the doc after modification instead of before.
+// Resets after execution.
+func (mog *Mog) ReturnAfter() {
+	mog.returnAfter = true
+}
+
+// SetRetries sets the number of times a failed read or write is retried.
+// Only errors the retry classifier considers retryable are retried (see SetRetryClassifier).
+// Default is 0, no retries.
+func (mog *Mog) SetRetries(n int) {
+	mog.retries = n
+}
+
+// SetRetryClassifier sets the function used to decide if an error should be retried, for reads and writes.
+// Use to retry additional errors, such as an app-level sentinel error. Only retry write errors when
+// the writes are idempotent. Pass nil to restore DefaultRetryClassifier (reads) and DefaultWriteRetryClassifier (writes).
+func (mog *Mog) SetRetryClassifier(fn func(err error) bool) {
+	mog.retryClassifier = fn
+}
+
+// SetWriteRateLimit limits Insert, Update, Replace, UpdateId, Delete.., Deduplicate and BulkWrite to opsPerSecond operations,
+// so background backfills don't starve production traffic. Each inserted doc and each bulk write model counts as 1 operation.
+// BulkWrite is split into chunks of at most opsPerSecond models when a limit is set.
+// BulkBatch and BulkWriter created after the limit is set share it.
+// Use 0 to remove the limit.
+func (mog *Mog) SetWriteRateLimit(opsPerSecond int) {
+	if opsPerSecond <= 0 {
+		mog.writeLimiter = nil
+		return
+	}
+	mog.writeLimiter = newRateLimiter(opsPerSecond)
+}
+
+// waitToWrite blocks until the write rate limit allows n more operations.
+func (mog *Mog) waitToWrite(n int) error {
+	if mog.writeLimiter == nil {
+		return nil
+	}
+	return mog.writeLimiter.wait(mog.ctx, n)
+}
+
+// DefaultRetryClassifier is used for reads, it returns true for errors labeled retryable by the driver, network errors,
+// and timeouts. Context errors (deadline exceeded, canceled) are not retried, another attempt would fail immediately.
+func DefaultRetryClassifier(err error) bool {
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var labeled mongo.LabeledError
+	if errors.As(err, &labeled) {
+		if labeled.HasErrorLabel("RetryableWriteError") || labeled.HasErrorLabel("TransientTransactionError") {
+			return true
+		}
+	}
+	return mongo.IsNetworkError(err) || mongo.IsTimeout(err)
+}
+
+// DefaultWriteRetryClassifier is used for writes, it returns true only for errors labeled "RetryableWriteError" by the driver.
+// Other network errors and timeouts are not retried, the server may have applied the write
+// (retrying could insert docs twice or apply $inc twice).
+func DefaultWriteRetryClassifier(err error) bool {
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var labeled mongo.LabeledError
+	return errors.As(err, &labeled) && labeled.HasErrorLabel("RetryableWriteError")
+}
+
+// retry runs read op, repeating it up to mog.retries times while the error returned is retryable.
+// The circuit breaker (if set) sees op and its retries as 1 operation, the read timeout applies to all of them.
+func (mog *Mog) retry(op func() error) error {
+	return mog.timed(mog.readTimeout, func() error { return mog.guard(mog.retryLoop(op, DefaultRetryClassifier)) })
+}
+
+// retryWrite works like retry for write op, using the write timeout and DefaultWriteRetryClassifier.
+func (mog *Mog) retryWrite(op func() error) error {
+	return mog.timed(mog.writeTimeout, func() error { return mog.guard(mog.retryLoop(op, DefaultWriteRetryClassifier)) })
+}
+
+// retryLoop returns func running op, repeating it up to mog.retries times while the error returned is retryable.
+// The classifier set by SetRetryClassifier is used if not nil, else defaultClassifier. No retries once mog.ctx is done.
+func (mog *Mog) retryLoop(op func() error, defaultClassifier func(error) bool) func() error {
+	classifier := mog.retryClassifier
+	if classifier == nil {
+		classifier = defaultClassifier
+	}
+	return func() error {
+		err := op()
+		for i := 0; i < mog.retries && err != nil && mog.ctx.Err() == nil && classifier(err); i++ {
+			err = op()
+		}
+		return err
+	}
+}
+
+// SetDefaultTimeout sets the maximum time for each read and each write operation (including retries),
+// so a forgotten index can't hang a caller indefinitely. Operations fail with context.DeadlineExceeded.
+// Applies to all doc reads (Find.., Count, Exists, Distinct.., AggRun.., Checksum, Percentiles, etc.) and writes
+// (Insert, Update.., Replace, Save, Delete.., FindOneAnd.., Deduplicate deletes and each BulkWrite chunk).
+// For methods streaming results (Find, FindUntil, FindChan, AggRun, AggCsvStream, Deduplicate) only opening the
+// cursor is limited, iterating it is not. Index, admin, Watch and GridFS methods and BulkBatch/BulkWriter are not limited.
+// Use 0 for no timeout (default). The timeout is added to mog's context (see WithContext).
+func (mog *Mog) SetDefaultTimeout(read, write time.Duration) {
+	mog.readTimeout = read
+	mog.writeTimeout = write
+}
+
+// timed runs op with mog.ctx limited to timeout, if timeout > 0.
+func (mog *Mog) timed(timeout time.Duration, op func() error) error {
+	if timeout <= 0 {
+		return op()
+	}
+	ctx := mog.ctx
+	var cancel context.CancelFunc
+	mog.ctx, cancel = context.WithTimeout(ctx, timeout)
+	defer func() {
+		cancel()
+		mog.ctx = ctx
+	}()
+	return op()
+}
+
+// findOptions returns options for Find methods using sortFlds and settings made by
+// Keep/Omit, SetLimit, SetSkip, SetBatchSize, SetHint, SetCollation and SetMaxTime. All but Keep/Omit are reset.
+func (mog *Mog) findOptions(sortFlds []string) *options.FindOptions {
 	findOptions := options.Find()
 	if len(sortFlds) > 0 {
 		sortOrder := CreateSortOrder(sortFlds)
@@ -109,38 +508,145 @@ func (mog *Mog) Find(criteria interface{}, sortFlds ...string) {
 		findOptions.SetLimit(mog.limit)
 		mog.limit = 0
 	}
+	if mog.skip > 0 {
+		findOptions.SetSkip(mog.skip)
+		mog.skip = 0
+	}
+	if mog.batchSize > 0 {
+		findOptions.SetBatchSize(mog.batchSize)
+		mog.batchSize = 0
+	}
+	if mog.hint != nil {
+		findOptions.SetHint(mog.hint)
+		mog.hint = nil
+	}
+	if mog.collation != nil {
+		findOptions.SetCollation(mog.collation)
+		mog.collation = nil
+	}
+	if mog.maxTime > 0 {
+		findOptions.SetMaxTime(mog.maxTime)
+		mog.maxTime = 0
+	}
+	return findOptions
+}
+
+// aggOptions returns options for Agg.. methods using settings made by SetBatchSize, SetMaxTime and AggAllowDiskUse.
+// Settings are reset.
+// Options passed by the caller are applied after these, overriding them.
+func (mog *Mog) aggOptions() *options.AggregateOptions {
+	aggOptions := options.Aggregate()
+	if mog.batchSize > 0 {
+		aggOptions.SetBatchSize(mog.batchSize)
+		mog.batchSize = 0
+	}
+	if mog.maxTime > 0 {
+		aggOptions.SetMaxTime(mog.maxTime)
+		mog.maxTime = 0
+	}
+	if mog.allowDiskUse {
+		aggOptions.SetAllowDiskUse(true)
+		mog.allowDiskUse = false
+	}
+	return aggOptions
+}
+
+// Find sets mog.iter = mongo cursor (iterator) for docs meeting criteria.
+// Next() method uses mog.iter to iterate thru results.
+// Use criteria parm to filter results (nil for all docs in collection).
+// Use optional sortFlds to sort. Begin fieldname with "-" for descending.
+// If the query fails, the error is returned and also available from IterErr(). Next() returns false.
+func (mog *Mog) Find(criteria interface{}, sortFlds ...string) error {
+	findOptions := mog.findOptions(sortFlds)
 	if criteria == nil {
 		criteria = bson.D{{}}
 	}
-	cursor, _ := mog.collection.Find(mog.ctx, criteria, findOptions)
+	criteria = mog.notDeleted(criteria)
+	var cursor *mongo.Cursor
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			var err error
+			cursor, err = mog.collection.Find(mog.ctx, criteria, findOptions)
+			return err
+		})
+	})
 	mog.iter = cursor
+	mog.iterErr = err
+	return err
 }
 
 // FindAll loads all matching docs into slice.
 // Parm "docs" should be address of target slice where results will be loaded.
 // Otherwise, works same as Find().
 func (mog *Mog) FindAll(criteria interface{}, docs interface{}, sortFlds ...string) error {
-	findOptions := options.Find()
-	if len(sortFlds) > 0 {
-		sortOrder := CreateSortOrder(sortFlds)
-		findOptions.SetSort(sortOrder)
+	findOptions := mog.findOptions(sortFlds)
+	if criteria == nil {
+		criteria = make(bson.D, 0)
+	}
+	criteria = mog.notDeleted(criteria)
+	err := mog.retry(func() error {
+		cursor, err := mog.collection.Find(mog.ctx, criteria, findOptions)
+		if err != nil {
+			return err
+		}
+		return cursor.All(mog.ctx, docs)
+	})
+	if err != nil {
+		return err
 	}
+	return afterFindAll(docs)
+}
+
+// FindRandom loads docs with n randomly selected docs (fewer if the collection has fewer), useful for spot checks.
+// Keep/Omit fields are applied. AggPipeline is not changed.
+func (mog *Mog) FindRandom(n int, docs interface{}) error {
+	pipeline := []bson.M{{"$sample": bson.M{"size": n}}}
 	if mog.projectFlds != nil {
-		findOptions.SetProjection(mog.projectFlds)
+		pipeline = append(pipeline, bson.M{"$project": mog.projectFlds})
 	}
-	if mog.limit > 0 {
-		findOptions.SetLimit(mog.limit)
-		mog.limit = 0
+	pipeline = mog.notDeletedPipeline(pipeline)
+	err := mog.retry(func() error {
+		cursor, err := mog.collection.Aggregate(mog.ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		return cursor.All(mog.ctx, docs)
+	})
+	if err != nil {
+		return err
 	}
+	return afterFindAll(docs)
+}
+
+// FindUntil iterates docs matching criteria, calling fn with each raw doc until fn returns stop = true.
+// Iteration also ends if fn returns an error, which is returned by FindUntil.
+// The cursor is always closed before returning, no need to call CloseIter.
+// Use bson.Unmarshal(raw, &doc) inside fn to decode the doc.
+func (mog *Mog) FindUntil(criteria interface{}, fn func(raw bson.Raw) (stop bool, err error), sortFlds ...string) error {
+	findOptions := mog.findOptions(sortFlds)
 	if criteria == nil {
-		criteria = make(bson.D, 0)
+		criteria = bson.D{}
 	}
-	cursor, err := mog.collection.Find(mog.ctx, criteria, findOptions)
+	criteria = mog.notDeleted(criteria)
+	var cursor *mongo.Cursor
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			var err error
+			cursor, err = mog.collection.Find(mog.ctx, criteria, findOptions)
+			return err
+		})
+	})
 	if err != nil {
 		return err
 	}
-	err = cursor.All(mog.ctx, docs)
-	return err
+	defer cursor.Close(mog.ctx)
+	for cursor.Next(mog.ctx) {
+		stop, err := fn(cursor.Current)
+		if err != nil || stop {
+			return err
+		}
+	}
+	return cursor.Err()
 }
 
 // FindOne returns the 1st doc found based on criteria and sort order.
@@ -155,16 +661,43 @@ func (mog *Mog) FindOne(criteria interface{}, doc interface{}, sortFlds ...strin
 	if mog.projectFlds != nil {
 		findOptions.SetProjection(mog.projectFlds)
 	}
-	err := mog.collection.FindOne(mog.ctx, criteria, findOptions).Decode(doc)
-	return err
+	if mog.skip > 0 {
+		findOptions.SetSkip(mog.skip)
+		mog.skip = 0
+	}
+	if mog.hint != nil {
+		findOptions.SetHint(mog.hint)
+		mog.hint = nil
+	}
+	if mog.collation != nil {
+		findOptions.SetCollation(mog.collation)
+		mog.collation = nil
+	}
+	if mog.maxTime > 0 {
+		findOptions.SetMaxTime(mog.maxTime)
+		mog.maxTime = 0
+	}
+	criteria = mog.notDeleted(criteria)
+	err := mog.retry(func() error {
+		return mog.collection.FindOne(mog.ctx, criteria, findOptions).Decode(doc)
+	})
+	if err != nil {
+		return err
+	}
+	return afterFind(doc)
 }
 
 // FindId returns doc with matching _id.
 // Parm "doc" should be address of target where result will be loaded.
 func (mog *Mog) FindId(docId interface{}, doc interface{}) error {
-	criteria := bson.M{"_id": docId}
-	err := mog.collection.FindOne(mog.ctx, criteria).Decode(doc)
-	return err
+	criteria := mog.notDeleted(bson.M{"_id": docId})
+	err := mog.retry(func() error {
+		return mog.collection.FindOne(mog.ctx, criteria).Decode(doc)
+	})
+	if err != nil {
+		return err
+	}
+	return afterFind(doc)
 }
 
 // Next loads next doc returned by mog.iter (cursor) created by previously run Find().
@@ -173,6 +706,9 @@ func (mog *Mog) FindId(docId interface{}, doc interface{}) error {
 // After completion, usg mog.IterErr() to get error value.
 // Iterator is automatically closed after last result processed.
 func (mog *Mog) Next(doc interface{}) bool {
+	if mog.iter == nil { // Find or AggRun failed
+		return false
+	}
 	more := mog.iter.Next(mog.ctx)
 	if !more {
 		mog.iterErr = mog.iter.Err()
@@ -181,7 +717,11 @@ func (mog *Mog) Next(doc interface{}) bool {
 	}
 	err := mog.iter.Decode(doc)
 	if err != nil {
-		log.Println("mog.Next decode error", mog.collectionName, err)
+		mog.log().Error("mog.Next decode error "+mog.collectionName, err)
+		mog.iterErr = err
+		return false
+	}
+	if err = afterFind(doc); err != nil {
 		mog.iterErr = err
 		return false
 	}
@@ -195,6 +735,9 @@ func (mog *Mog) IterErr() error {
 
 // CloseIter closes mog.iter. Use if all results not processed by Next().
 func (mog *Mog) CloseIter() error {
+	if mog.iter == nil {
+		return nil
+	}
 	err := mog.iter.Close(mog.ctx)
 	return err
 }
@@ -206,74 +749,897 @@ func (mog *Mog) Count(criteria interface{}) (int64, error) {
 		countOptions.SetLimit(mog.limit)
 		mog.limit = 0
 	}
-	count, err := mog.collection.CountDocuments(mog.ctx, criteria, countOptions)
+	if mog.hint != nil {
+		countOptions.SetHint(mog.hint)
+		mog.hint = nil
+	}
+	if mog.collation != nil {
+		countOptions.SetCollation(mog.collation)
+		mog.collation = nil
+	}
+	if mog.maxTime > 0 {
+		countOptions.SetMaxTime(mog.maxTime)
+		mog.maxTime = 0
+	}
+	criteria = mog.notDeleted(criteria)
+	var count int64
+	err := mog.retry(func() error {
+		var err error
+		count, err = mog.collection.CountDocuments(mog.ctx, criteria, countOptions)
+		return err
+	})
 	return count, err
 }
 
+// Exists returns true if at least 1 doc matches criteria.
+// Faster than Count when only presence matters, the search stops at the 1st match and only _id is returned.
+func (mog *Mog) Exists(criteria interface{}) (bool, error) {
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	criteria = mog.notDeleted(criteria)
+	opts := options.FindOne().SetProjection(bson.M{"_id": 1})
+	err := mog.retry(func() error {
+		return mog.collection.FindOne(mog.ctx, criteria, opts).Err()
+	})
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Distinct returns the distinct values of fieldName in docs matching criteria (nil for all docs).
+func (mog *Mog) Distinct(fieldName string, criteria interface{}) ([]interface{}, error) {
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	criteria = mog.notDeleted(criteria)
+	var values []interface{}
+	err := mog.retry(func() error {
+		var err error
+		values, err = mog.collection.Distinct(mog.ctx, fieldName, criteria)
+		return err
+	})
+	return values, err
+}
+
+// DistinctInto works like Distinct except values are loaded into target.
+// Parm "target" should be address of slice of the field's type, such as *[]string.
+func (mog *Mog) DistinctInto(fieldName string, criteria interface{}, target interface{}) error {
+	values, err := mog.Distinct(fieldName, criteria)
+	if err != nil {
+		return err
+	}
+	raw, err := bson.Marshal(bson.M{"values": values})
+	if err != nil {
+		return err
+	}
+	return bson.Raw(raw).Lookup("values").Unmarshal(target)
+}
+
 // Update updates docs matching parm "criteria" using parm "update".
 // To update all docs, criteria should be type bson.D with no elements - bson.D{}.
 func (mog *Mog) Update(criteria, update interface{}) (int64, error) {
 	if criteria == nil {
 		return 0, errors.New("nil criteria not allowed for update")
 	}
+	if err := beforeUpdate(update); err != nil {
+		return 0, err
+	}
+	if mog.autoTimestamps {
+		var err error
+		if update, err = timestampUpdate(update); err != nil {
+			return 0, err
+		}
+	}
+	updateOptions := mog.updateOptions()
+	if mog.dryRun {
+		return 0, mog.dryRunLog("update", criteria, update)
+	}
+	before, err := mog.auditBefore(criteria, true)
+	if err != nil {
+		return 0, err
+	}
+	if err = mog.waitToWrite(1); err != nil {
+		return 0, err
+	}
+	var changeInfo *mongo.UpdateResult
+	err = mog.retryWrite(func() error {
+		var err error
+		changeInfo, err = mog.collection.UpdateMany(mog.ctx, criteria, update, updateOptions)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	count := changeInfo.ModifiedCount + changeInfo.UpsertedCount
+	if count > 0 {
+		err = mog.auditLog("update", criteria, []interface{}{update}, before)
+	}
+	return count, err
+}
+
+// updateOptions returns options for Update methods using settings made by Upsert, SetHint and SetCollation.
+// Settings are reset.
+func (mog *Mog) updateOptions() *options.UpdateOptions {
 	updateOptions := options.Update()
 	if mog.upsert { // if true, insert docs not matching criteria
 		updateOptions.SetUpsert(true)
 		mog.upsert = false
 	}
-	changeInfo, err := mog.collection.UpdateMany(mog.ctx, criteria, update, updateOptions)
-	return changeInfo.ModifiedCount + changeInfo.UpsertedCount, err
+	if mog.hint != nil {
+		updateOptions.SetHint(mog.hint)
+		mog.hint = nil
+	}
+	if mog.collation != nil {
+		updateOptions.SetCollation(mog.collation)
+		mog.collation = nil
+	}
+	return updateOptions
+}
+
+// Replace replaces 1st doc matching criteria, with newDoc.
+func (mog *Mog) Replace(criteria, newDoc interface{}) error {
+	replaceOptions := options.Replace()
+	if mog.upsert { // insert new doc, if no doc found matching criteria
+		replaceOptions.SetUpsert(true)
+		mog.upsert = false
+	}
+	_, err := mog.replaceOne(criteria, newDoc, replaceOptions)
+	return err
+}
+
+// Save replaces the doc having the same _id as doc, or inserts doc if none exists.
+// The _id is taken from doc (bson tag "_id"), it must be present.
+func (mog *Mog) Save(doc interface{}) error {
+	if err := beforeUpdate(doc); err != nil {
+		return err
+	}
+	if mog.autoTimestamps {
+		var err error
+		if doc, err = timestampDoc(doc, false); err != nil {
+			return err
+		}
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	docId, err := bson.Raw(raw).LookupErr("_id")
+	if err != nil {
+		return errors.New("doc has no _id, cannot save")
+	}
+	criteria := bson.M{"_id": docId}
+	if mog.dryRun {
+		return mog.dryRunLog("save", criteria, raw)
+	}
+	before, err := mog.auditBefore(criteria, false)
+	if err != nil {
+		return err
+	}
+	if err = mog.waitToWrite(1); err != nil {
+		return err
+	}
+	opts := options.Replace().SetUpsert(true)
+	err = mog.retryWrite(func() error {
+		_, err := mog.collection.ReplaceOne(mog.ctx, criteria, raw, opts)
+		return err
+	})
+	if err == nil {
+		err = mog.auditLog("save", criteria, []interface{}{raw}, before)
+	}
+	return err
+}
+
+// FindOneAndUpdate atomically updates the 1st doc matching criteria and sort order, and loads it into doc.
+// Parm "doc" should be address of target where result will be loaded.
+// By default doc is loaded with the doc before modification, call ReturnAfter() for the modified doc.
+// Upsert() toggle is honored. If error == mongo.ErrNoDocuments, no docs found matching criteria.
+func (mog *Mog) FindOneAndUpdate(criteria, update, doc interface{}, sortFlds ...string) error {
+	if err := beforeUpdate(update); err != nil {
+		return err
+	}
+	opts := options.FindOneAndUpdate()
+	if len(sortFlds) > 0 {
+		opts.SetSort(CreateSortOrder(sortFlds))
+	}
+	if mog.projectFlds != nil {
+		opts.SetProjection(mog.projectFlds)
+	}
+	upsert, returnAfter := mog.upsert, mog.returnAfter
+	if mog.upsert {
+		opts.SetUpsert(true)
+		mog.upsert = false
+	}
+	if mog.returnAfter {
+		opts.SetReturnDocument(options.After)
+		mog.returnAfter = false
+	}
+	if mog.dryRun {
+		return ErrDryRun
+	}
+	if mog.autoTimestamps {
+		var err error
+		if update, err = timestampUpdate(update); err != nil {
+			return err
+		}
+	}
+	if err := mog.waitToWrite(1); err != nil {
+		return err
+	}
+	var found bson.Raw
+	err := mog.retryWrite(func() error {
+		result := mog.collection.FindOneAndUpdate(mog.ctx, mog.notDeleted(criteria), update, opts)
+		found, _ = result.Raw()
+		return result.Decode(doc)
+	})
+	if err == nil || (err == mongo.ErrNoDocuments && upsert) { // no doc returned when upserted without ReturnAfter
+		if auditErr := mog.auditFound("update", criteria, update, found, returnAfter); auditErr != nil {
+			return auditErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return afterFind(doc)
+}
+
+// FindOneAndReplace atomically replaces the 1st doc matching criteria and sort order with newDoc.
+// Parm "oldDoc" is loaded with the replaced doc (or newDoc if ReturnAfter() was called).
+// Upsert() toggle is honored. If error == mongo.ErrNoDocuments, no docs found matching criteria.
+func (mog *Mog) FindOneAndReplace(criteria, newDoc, oldDoc interface{}, sortFlds ...string) error {
+	if err := beforeUpdate(newDoc); err != nil {
+		return err
+	}
+	opts := options.FindOneAndReplace()
+	if len(sortFlds) > 0 {
+		opts.SetSort(CreateSortOrder(sortFlds))
+	}
+	if mog.projectFlds != nil {
+		opts.SetProjection(mog.projectFlds)
+	}
+	upsert, returnAfter := mog.upsert, mog.returnAfter
+	if mog.upsert {
+		opts.SetUpsert(true)
+		mog.upsert = false
+	}
+	if mog.returnAfter {
+		opts.SetReturnDocument(options.After)
+		mog.returnAfter = false
+	}
+	if mog.dryRun {
+		return ErrDryRun
+	}
+	if mog.autoTimestamps {
+		var err error
+		if newDoc, err = timestampDoc(newDoc, false); err != nil {
+			return err
+		}
+	}
+	if err := mog.waitToWrite(1); err != nil {
+		return err
+	}
+	var found bson.Raw
+	err := mog.retryWrite(func() error {
+		result := mog.collection.FindOneAndReplace(mog.ctx, mog.notDeleted(criteria), newDoc, opts)
+		found, _ = result.Raw()
+		return result.Decode(oldDoc)
+	})
+	if err == nil || (err == mongo.ErrNoDocuments && upsert) { // no doc returned when upserted without ReturnAfter
+		if auditErr := mog.auditFound("replace", criteria, newDoc, found, returnAfter); auditErr != nil {
+			return auditErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return afterFind(oldDoc)
+}
+
+// FindOneAndDelete atomically deletes the 1st doc matching criteria and sort order, and loads it into doc.
+// Useful for queue style processing where each doc should be consumed once.
+// If error == mongo.ErrNoDocuments, no docs found matching criteria.
+func (mog *Mog) FindOneAndDelete(criteria, doc interface{}, sortFlds ...string) error {
+	opts := options.FindOneAndDelete()
+	if len(sortFlds) > 0 {
+		opts.SetSort(CreateSortOrder(sortFlds))
+	}
+	if mog.projectFlds != nil {
+		opts.SetProjection(mog.projectFlds)
+	}
+	if mog.dryRun {
+		return ErrDryRun
+	}
+	var found bson.Raw
+	var err error
+	if mog.softDeleteFld != "" {
+		found, err = mog.softDeleteOne(criteria, doc, opts)
+	} else if err = mog.waitToWrite(1); err == nil {
+		err = mog.retryWrite(func() error {
+			result := mog.collection.FindOneAndDelete(mog.ctx, criteria, opts)
+			found, _ = result.Raw()
+			return result.Decode(doc)
+		})
+	}
+	if err == nil {
+		err = mog.auditFound("delete", criteria, nil, found, false)
+	}
+	return err
+}
+
+// UpdateId updates doc with matching id.
+func (mog *Mog) UpdateId(docId, update interface{}) error {
+	criteria := bson.M{"_id": docId}
+	if err := beforeUpdate(update); err != nil {
+		return err
+	}
+	if mog.autoTimestamps {
+		var err error
+		if update, err = timestampUpdate(update); err != nil {
+			return err
+		}
+	}
+	if mog.dryRun {
+		return mog.dryRunLog("update id", criteria, update)
+	}
+	before, err := mog.auditBefore(criteria, false)
+	if err != nil {
+		return err
+	}
+	if err = mog.waitToWrite(1); err != nil {
+		return err
+	}
+	var result *mongo.UpdateResult
+	err = mog.retryWrite(func() error {
+		var err error
+		result, err = mog.collection.UpdateOne(mog.ctx, criteria, update)
+		return err
+	})
+	if err == nil && result.ModifiedCount > 0 {
+		err = mog.auditLog("update", criteria, []interface{}{update}, before)
+	}
+	return err
+}
+
+// Insert adds 1 or more documents to collection (use Bulk for large number of inserts).
+func (mog *Mog) Insert(docs ...interface{}) error {
+	if err := beforeInsert(docs); err != nil {
+		return err
+	}
+	if mog.autoTimestamps {
+		stamped := make([]interface{}, len(docs))
+		for i, doc := range docs {
+			var err error
+			if stamped[i], err = timestampDoc(doc, true); err != nil {
+				return err
+			}
+		}
+		docs = stamped
+	}
+	if mog.dryRun {
+		for _, doc := range docs {
+			mog.dryRunLog("insert", nil, doc)
+		}
+		return nil
+	}
+	if err := mog.waitToWrite(len(docs)); err != nil {
+		return err
+	}
+	err := mog.retryWrite(func() error {
+		_, err := mog.collection.InsertMany(mog.ctx, docs)
+		return err
+	})
+	if err == nil {
+		err = mog.auditLog("insert", nil, docs, nil)
+	}
+	return err
+}
+
+// DeleteOne deletes the 1st doc matching criteria. Returns count of docs deleted (0 or 1).
+func (mog *Mog) DeleteOne(criteria interface{}) (int64, error) {
+	if criteria == nil {
+		return 0, errors.New("nil criteria not allowed for delete")
+	}
+	if mog.dryRun {
+		return 0, mog.dryRunLog("delete one", criteria, nil)
+	}
+	before, err := mog.auditBefore(mog.notDeleted(criteria), false)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if mog.softDeleteFld != "" {
+		count, err = mog.softDelete(criteria, false)
+	} else {
+		count, err = mog.deleteDocs(criteria, false)
+	}
+	if err == nil && count > 0 {
+		err = mog.auditLog("delete", criteria, nil, before)
+	}
+	return count, err
+}
+
+// DeleteMany deletes all docs matching criteria. Returns count of docs deleted.
+// To delete all docs, criteria should be type bson.D with no elements - bson.D{}.
+func (mog *Mog) DeleteMany(criteria interface{}) (int64, error) {
+	if criteria == nil {
+		return 0, errors.New("nil criteria not allowed for delete")
+	}
+	if mog.dryRun {
+		return 0, mog.dryRunLog("delete", criteria, nil)
+	}
+	before, err := mog.auditBefore(mog.notDeleted(criteria), true)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if mog.softDeleteFld != "" {
+		count, err = mog.softDelete(criteria, true)
+	} else {
+		count, err = mog.deleteDocs(criteria, true)
+	}
+	if err == nil && count > 0 {
+		err = mog.auditLog("delete", criteria, nil, before)
+	}
+	return count, err
+}
+
+// deleteDocs deletes the 1st (or all if many) docs matching criteria. Returns count of docs deleted.
+func (mog *Mog) deleteDocs(criteria interface{}, many bool) (int64, error) {
+	if err := mog.waitToWrite(1); err != nil {
+		return 0, err
+	}
+	var result *mongo.DeleteResult
+	err := mog.retryWrite(func() error {
+		var err error
+		if many {
+			result, err = mog.collection.DeleteMany(mog.ctx, criteria)
+		} else {
+			result, err = mog.collection.DeleteOne(mog.ctx, criteria)
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteManyChunked deletes all docs matching criteria in chunks of at most chunkSize docs, to avoid stalling
+// the cluster with one huge delete. Each chunk's _ids are found, then deleted, followed by a pause (0 for none).
+// Progress (docs deleted so far) is logged after each chunk (logging is off unless SetLogger is called),
+// and passed to parm "progress" if given. Returns count of docs deleted.
+// Ex: deleted, err := mog.DeleteManyChunked(bson.M{"status": "expired"}, 1000, 100*time.Millisecond)
+func (mog *Mog) DeleteManyChunked(criteria interface{}, chunkSize int, pause time.Duration, progress ...func(deleted int64)) (int64, error) {
+	if criteria == nil {
+		return 0, errors.New("nil criteria not allowed for delete")
+	}
+	if chunkSize < 1 {
+		return 0, errors.New("chunk size must be at least 1")
+	}
+	if mog.dryRun {
+		return 0, mog.dryRunLog("delete chunked", criteria, nil)
+	}
+	findOptions := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(int64(chunkSize))
+	var deleted int64
+	for {
+		var idDocs []struct {
+			Id interface{} `bson:"_id"`
+		}
+		err := mog.retry(func() error {
+			cursor, err := mog.collection.Find(mog.ctx, mog.notDeleted(criteria), findOptions)
+			if err != nil {
+				return err
+			}
+			return cursor.All(mog.ctx, &idDocs)
+		})
+		if err != nil || len(idDocs) == 0 {
+			return deleted, err
+		}
+		ids := make(bson.A, len(idDocs))
+		for i, idDoc := range idDocs {
+			ids[i] = idDoc.Id
+		}
+		// criteria repeated in case docs changed since found
+		count, err := mog.DeleteMany(bson.M{"$and": bson.A{criteria, bson.M{"_id": bson.M{"$in": ids}}}})
+		deleted += count
+		if err != nil {
+			return deleted, err
+		}
+		mog.log().Printf("mog DeleteManyChunked %s: %d deleted", mog.collectionName, deleted)
+		for _, report := range progress {
+			report(deleted)
+		}
+		if len(idDocs) < chunkSize {
+			return deleted, nil
+		}
+		if pause > 0 {
+			select {
+			case <-time.After(pause):
+			case <-mog.ctx.Done():
+				return deleted, mog.ctx.Err()
+			}
+		}
+	}
+}
+
+// DeleteId deletes doc with matching id.
+// If no doc has matching id, mongo.ErrNoDocuments is returned (not in dry run mode).
+func (mog *Mog) DeleteId(docId interface{}) error {
+	count, err := mog.DeleteOne(bson.M{"_id": docId})
+	if err == nil && count == 0 && !mog.dryRun {
+		err = mongo.ErrNoDocuments
+	}
+	return err
+}
+
+// Deduplicate removes docs having the same values for all keyFields, keeping the doc with the lowest _id.
+// Docs missing any of the keyFields are never removed. Each group of duplicates is removed using DeleteMany,
+// so soft delete, audit and dry run apply (soft deleted docs are not considered). Returns the number of docs removed.
+func (mog *Mog) Deduplicate(keyFields ...string) (int64, error) {
+	if len(keyFields) == 0 {
+		return 0, errors.New("at least 1 key field required for deduplicate")
+	}
+	groupId := make(bson.M)
+	hasKeys := make(bson.M) // docs missing a key field are not duplicates of each other
+	for _, fld := range keyFields {
+		groupId[fld] = "$" + fld
+		hasKeys[fld] = bson.M{"$exists": true}
+	}
+	pipeline := []bson.M{
+		{"$match": hasKeys},
+		{"$sort": bson.M{"_id": 1}},
+		{"$group": bson.M{"_id": groupId, "ids": bson.M{"$push": "$_id"}, "count": bson.M{"$sum": 1}}},
+		{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+	}
+	pipeline = mog.notDeletedPipeline(pipeline)
+	opts := options.Aggregate().SetAllowDiskUse(true)
+	var cursor *mongo.Cursor
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			var err error
+			cursor, err = mog.collection.Aggregate(mog.ctx, pipeline, opts)
+			return err
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(mog.ctx)
+	var removed int64
+	var group struct {
+		Ids []interface{} `bson:"ids"`
+	}
+	for cursor.Next(mog.ctx) {
+		if err = cursor.Decode(&group); err != nil {
+			return removed, err
+		}
+		count, err := mog.DeleteMany(bson.M{"_id": bson.M{"$in": group.Ids[1:]}})
+		removed += count
+		if err != nil {
+			return removed, err
+		}
+	}
+	return removed, cursor.Err()
+}
+
+// BulkStart called at beginning of bulk write process, size is estimated # of updates.
+func (mog *Mog) BulkStart(size int) {
+	mog.bulkWrites = make([]mongo.WriteModel, 0, size)
+	mog.bulkFlushResult = BulkWriteResult{}
+	mog.bulkFlushed = 0
+	mog.bulkFlushErr = nil
+}
+
+// BulkAutoFlush makes BulkAdd.. methods write the pending entries whenever threshold entries are waiting,
+// so long running import loops need not manage batch boundaries. Call BulkWrite at the end to write the rest,
+// it returns the total written including auto flushes, and the error of a failed auto flush.
+// After a failed auto flush, entries are no longer added or written (BulkWrite discards those waiting).
+// Check BulkErr in the import loop to stop early. Remains in effect until BulkAutoFlush(0) is called.
+func (mog *Mog) BulkAutoFlush(threshold int) {
+	mog.bulkFlushAt = threshold
+}
+
+// BulkErr returns the error of a failed auto flush or BulkAdd.. hook since BulkStart, nil if none.
+// Once set, BulkAdd.. methods drop their entries and BulkWrite returns the error.
+func (mog *Mog) BulkErr() error {
+	return mog.bulkFlushErr
+}
+
+// bulkAdd appends model to mog.BulkWrites, writing pending entries if the BulkAutoFlush threshold is reached.
+// The model is dropped if an auto flush or hook has failed.
+func (mog *Mog) bulkAdd(model mongo.WriteModel) {
+	if mog.bulkFlushErr != nil {
+		return
+	}
+	mog.bulkWrites = append(mog.bulkWrites, model)
+	if mog.bulkFlushAt <= 0 || len(mog.bulkWrites) < mog.bulkFlushAt {
+		return
+	}
+	models := mog.bulkWrites
+	mog.bulkWrites = make([]mongo.WriteModel, 0, mog.bulkFlushAt)
+	err := mog.bulkWriteModels(models, mog.bulkFlushed, &mog.bulkFlushResult)
+	mog.bulkFlushed += len(models)
+	mog.bulkFlushErr = err
+}
+
+// bulkHookFailed makes BulkWrite return err (the 1st failure) instead of writing the entries.
+func (mog *Mog) bulkHookFailed(err error) {
+	if mog.bulkFlushErr == nil {
+		mog.bulkFlushErr = err
+	}
+}
+
+// BulkAddInsert adds documents to be inserted to mog.BulkWrites.
+// If BeforeInsert of doc returns an error, doc is not added and BulkWrite returns the error without writing.
+func (mog *Mog) BulkAddInsert(doc interface{}) {
+	if err := beforeInsert([]interface{}{doc}); err != nil {
+		mog.bulkHookFailed(err)
+		return
+	}
+	if mog.autoTimestamps {
+		doc, _ = timestampDoc(doc, true) // on error doc unchanged, BulkWrite reports the error
+	}
+	model := mongo.NewInsertOneModel()
+	model.SetDocument(doc)
+	mog.bulkAdd(model)
+}
+
+// BulkAddUpdate adds matching criteria and update doc to mog.BulkWrites.
+func (mog *Mog) BulkAddUpdate(criteria, update interface{}) {
+	if mog.autoTimestamps {
+		update, _ = timestampUpdate(update)
+	}
+	model := mongo.NewUpdateManyModel()
+	model.SetFilter(criteria)
+	model.SetUpdate(update)
+	mog.bulkAdd(model)
+}
+
+// BulkAddUpdateOne adds matching criteria and update doc to mog.BulkWrites, only the 1st doc matching criteria is updated.
+// If upsert is true, a doc is inserted when none matches criteria.
+func (mog *Mog) BulkAddUpdateOne(criteria, update interface{}, upsert bool) {
+	if mog.autoTimestamps {
+		update, _ = timestampUpdate(update)
+	}
+	model := mongo.NewUpdateOneModel()
+	model.SetFilter(criteria)
+	model.SetUpdate(update)
+	model.SetUpsert(upsert)
+	mog.bulkAdd(model)
+}
+
+// BulkAddReplace adds matching criteria and newDoc to mog.BulkWrites, the 1st doc matching criteria is replaced by newDoc.
+// If upsert is true, newDoc is inserted when no doc matches criteria.
+// If BeforeUpdate of newDoc returns an error, it is not added and BulkWrite returns the error without writing.
+func (mog *Mog) BulkAddReplace(criteria, newDoc interface{}, upsert bool) {
+	if err := beforeUpdate(newDoc); err != nil {
+		mog.bulkHookFailed(err)
+		return
+	}
+	if mog.autoTimestamps {
+		newDoc, _ = timestampDoc(newDoc, false)
+	}
+	model := mongo.NewReplaceOneModel()
+	model.SetFilter(criteria)
+	model.SetReplacement(newDoc)
+	model.SetUpsert(upsert)
+	mog.bulkAdd(model)
+}
+
+// BulkAddDelete adds matching criteria to mog.BulkWrites, all docs matching criteria are deleted.
+func (mog *Mog) BulkAddDelete(criteria interface{}) {
+	if mog.softDeleteFld != "" {
+		model := mongo.NewUpdateManyModel()
+		model.SetFilter(mog.notDeleted(criteria))
+		model.SetUpdate(softDeleteUpdate(mog.softDeleteFld))
+		mog.bulkAdd(model)
+		return
+	}
+	model := mongo.NewDeleteManyModel()
+	model.SetFilter(criteria)
+	mog.bulkAdd(model)
 }
 
-// Replace replaces 1st doc matching criteria, with newDoc.
-func (mog *Mog) Replace(criteria, newDoc interface{}) error {
-	replaceOptions := options.Replace()
-	if mog.upsert { // insert new doc, if no doc found matching criteria
-		replaceOptions.SetUpsert(true)
-		mog.upsert = false
+// BulkAddDeleteOne adds matching criteria to mog.BulkWrites, the 1st doc matching criteria is deleted.
+func (mog *Mog) BulkAddDeleteOne(criteria interface{}) {
+	if mog.softDeleteFld != "" {
+		model := mongo.NewUpdateOneModel()
+		model.SetFilter(mog.notDeleted(criteria))
+		model.SetUpdate(softDeleteUpdate(mog.softDeleteFld))
+		mog.bulkAdd(model)
+		return
 	}
-	_, err := mog.collection.ReplaceOne(mog.ctx, criteria, newDoc, replaceOptions)
-	return err
+	model := mongo.NewDeleteOneModel()
+	model.SetFilter(criteria)
+	mog.bulkAdd(model)
 }
 
-// UpdateId updates doc with matching id.
-func (mog *Mog) UpdateId(docId, update interface{}) error {
-	criteria := bson.M{"_id": docId}
-	_, err := mog.collection.UpdateOne(mog.ctx, criteria, update)
-	return err
+// BulkUnordered makes the next BulkWrite run unordered: the server may apply writes in any order (faster),
+// and writes continue after a failed write. Resets after execution.
+func (mog *Mog) BulkUnordered() {
+	mog.bulkUnordered = true
 }
 
-// Insert adds 1 or more documents to collection (use Bulk for large number of inserts).
-func (mog *Mog) Insert(docs ...interface{}) error {
-	_, err := mog.collection.InsertMany(mog.ctx, docs)
-	return err
+// BulkOpError describes a failed write of BulkWrite.
+type BulkOpError struct {
+	Index   int // position of the write in the entries added since BulkStart
+	Code    int // server error code, 11000 is duplicate key
+	Message string
 }
 
-// BulkStart called at beginning of bulk write process, size is estimated # of updates.
-func (mog *Mog) BulkStart(size int) {
-	mog.bulkWrites = make([]mongo.WriteModel, 0, size)
+// BulkErrors is the error returned by BulkWrite when writes fail, use errors.As to access.
+// With BulkUnordered all failed writes are included, otherwise only the 1st (writes after it are not attempted).
+type BulkErrors struct {
+	OpErrors        []BulkOpError
+	WriteConcernErr error // nil unless the write concern was not satisfied
 }
 
-// BulkAddInsert adds documents to be inserted to mog.BulkWrites.
-func (mog *Mog) BulkAddInsert(doc interface{}) {
-	model := mongo.NewInsertOneModel()
-	model.SetDocument(doc)
-	mog.bulkWrites = append(mog.bulkWrites, model)
+func (bulkErrors *BulkErrors) Error() string {
+	msg := strconv.Itoa(len(bulkErrors.OpErrors)) + " bulk write errors"
+	if len(bulkErrors.OpErrors) > 0 {
+		msg += ", 1st at index " + strconv.Itoa(bulkErrors.OpErrors[0].Index) + ": " + bulkErrors.OpErrors[0].Message
+	}
+	if bulkErrors.WriteConcernErr != nil {
+		msg += ", write concern error: " + bulkErrors.WriteConcernErr.Error()
+	}
+	return msg
 }
 
-// BulkAddUpdate adds matching criteria and update doc to mog.BulkWrites.
-func (mog *Mog) BulkAddUpdate(criteria, update interface{}) {
-	model := mongo.NewUpdateManyModel()
-	model.SetFilter(criteria)
-	model.SetUpdate(update)
-	mog.bulkWrites = append(mog.bulkWrites, model)
+// BulkWriteResult details the outcome of BulkWriteDetailed.
+type BulkWriteResult struct {
+	Inserted    int64
+	Matched     int64 // docs matched by updates and replaces
+	Modified    int64
+	Deleted     int64
+	Upserted    int64
+	UpsertedIds map[int]interface{} // _id of each upserted doc, keyed by position of the entry (see BulkOpError.Index)
+	OpErrors    []BulkOpError       // failed writes, same as in *BulkErrors
+}
+
+// Total returns total of docs inserted (including upserts), modified and deleted.
+func (result *BulkWriteResult) Total() int64 {
+	return result.Inserted + result.Upserted + result.Modified + result.Deleted
+}
+
+// add adds the counts of driverResult (for entries starting at position firstIndex) to result.
+func (result *BulkWriteResult) add(driverResult *mongo.BulkWriteResult, firstIndex int) {
+	result.Inserted += driverResult.InsertedCount
+	result.Matched += driverResult.MatchedCount
+	result.Modified += driverResult.ModifiedCount
+	result.Deleted += driverResult.DeletedCount
+	result.Upserted += driverResult.UpsertedCount
+	for index, id := range driverResult.UpsertedIDs {
+		if result.UpsertedIds == nil {
+			result.UpsertedIds = make(map[int]interface{})
+		}
+		result.UpsertedIds[firstIndex+int(index)] = id
+	}
 }
 
 // BulkWrite executes bulk write using entries in mog.BulkWrites.
+// Returns total of docs inserted (including upserts), modified and deleted.
+// If a write rate limit is set (see SetWriteRateLimit), entries are written in paced chunks.
+// If writes fail, the error is *BulkErrors listing each failed write.
+// With BulkAutoFlush, the total includes entries already written and a failed auto flush error is returned.
+// Use BulkWriteDetailed for counts by type of write and upserted ids.
 func (mog *Mog) BulkWrite() (int64, error) {
-	result, err := mog.collection.BulkWrite(mog.ctx, mog.bulkWrites)
+	result, err := mog.BulkWriteDetailed()
+	return result.Total(), err
+}
+
+// BulkWriteDetailed works like BulkWrite, returning the counts of each type of write, upserted ids and failed writes.
+func (mog *Mog) BulkWriteDetailed() (*BulkWriteResult, error) {
+	models := mog.bulkWrites
 	mog.bulkWrites = nil
-	return result.InsertedCount + result.ModifiedCount, err
+	result, flushed, err := mog.bulkFlushResult, mog.bulkFlushed, mog.bulkFlushErr
+	mog.bulkFlushResult, mog.bulkFlushed, mog.bulkFlushErr = BulkWriteResult{}, 0, nil
+	if err == nil {
+		err = mog.bulkWriteModels(models, flushed, &result)
+	}
+	mog.bulkUnordered = false
+	return &result, err
+}
+
+// bulkWriteModels executes bulk write using models, in chunks if a write rate limit is set, adding outcome to result.
+// Parm "firstIndex" is the position of models[0] in the entries added since BulkStart, used for BulkOpError.Index.
+func (mog *Mog) bulkWriteModels(models []mongo.WriteModel, firstIndex int, result *BulkWriteResult) error {
+	if mog.dryRun {
+		return mog.dryRunModels(models)
+	}
+	ordered := !mog.bulkUnordered
+	chunkSize := len(models)
+	if mog.writeLimiter != nil {
+		chunkSize = int(mog.writeLimiter.rate)
+	}
+	var bulkErrors BulkErrors
+	for offset := 0; offset < len(models); offset += chunkSize {
+		if chunkSize > len(models)-offset {
+			chunkSize = len(models) - offset
+		}
+		if err := mog.waitToWrite(chunkSize); err != nil {
+			return err
+		}
+		var driverResult *mongo.BulkWriteResult
+		err := mog.timed(mog.writeTimeout, func() error {
+			return mog.guard(func() error {
+				var err error
+				driverResult, err = mog.collection.BulkWrite(mog.ctx, models[offset:offset+chunkSize], options.BulkWrite().SetOrdered(ordered))
+				return err
+			})
+		})
+		var bulkException mongo.BulkWriteException
+		if err != nil && !errors.As(err, &bulkException) {
+			return err
+		}
+		if auditErr := mog.auditModels(writtenModels(models[offset:offset+chunkSize], err, ordered)); auditErr != nil {
+			return auditErr
+		}
+		if driverResult != nil {
+			result.add(driverResult, firstIndex+offset)
+		}
+		for _, writeErr := range bulkException.WriteErrors {
+			opError := BulkOpError{Index: firstIndex + offset + writeErr.Index, Code: writeErr.Code, Message: writeErr.Message}
+			bulkErrors.OpErrors = append(bulkErrors.OpErrors, opError)
+			result.OpErrors = append(result.OpErrors, opError)
+		}
+		if bulkException.WriteConcernError != nil {
+			bulkErrors.WriteConcernErr = bulkException.WriteConcernError
+		}
+		if err != nil && ordered {
+			break
+		}
+	}
+	if len(bulkErrors.OpErrors) > 0 || bulkErrors.WriteConcernErr != nil {
+		return &bulkErrors
+	}
+	return nil
+}
+
+// BulkFromChannel inserts docs received from ch in bulk writes of batchSize docs, until ch is closed.
+// Returns the number of docs inserted. Entries added by BulkAdd.. methods are not affected.
+// On error (a failed write, or mog's context done) it returns immediately without draining ch,
+// the producer should then stop sending (for example by sharing mog's context).
+// BulkUnordered and SetWriteRateLimit apply, failed writes are reported as *BulkErrors.
+// If BeforeInsert of a doc returns an error, it returns the error without writing the docs not yet written.
+func (mog *Mog) BulkFromChannel(ch <-chan interface{}, batchSize int) (int64, error) {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	defer func() { mog.bulkUnordered = false }()
+	var result BulkWriteResult
+	received := 0
+	models := make([]mongo.WriteModel, 0, batchSize)
+	for {
+		var doc interface{}
+		more := true
+		select {
+		case doc, more = <-ch:
+		case <-mog.ctx.Done():
+			return result.Inserted, mog.ctx.Err()
+		}
+		if more {
+			if err := beforeInsert([]interface{}{doc}); err != nil {
+				return result.Inserted, err
+			}
+			if mog.autoTimestamps {
+				doc, _ = timestampDoc(doc, true) // on error doc unchanged, the write reports the error
+			}
+			model := mongo.NewInsertOneModel()
+			model.SetDocument(doc)
+			models = append(models, model)
+		}
+		if len(models) == batchSize || (!more && len(models) > 0) {
+			if err := mog.bulkWriteModels(models, received, &result); err != nil {
+				return result.Inserted, err
+			}
+			received += len(models)
+			models = make([]mongo.WriteModel, 0, batchSize)
+		}
+		if !more {
+			return result.Inserted, nil
+		}
+	}
 }
 
 // Keep loads ProjectFlds with map of flds to be kept in Find results.
@@ -417,6 +1783,76 @@ func (mog *Mog) CsvInDone() {
 	mog.csvFile.Close()
 }
 
+// AggCsvStream runs the AggPipeline and writes results to w as csv, one record per result doc.
+// The 1st record contains the field names. Dot notation can be used for sub-document fields.
+// Results are written as they are read from the cursor, the full result is never held in memory.
+// Parm "allowDiskUse" lets large $group and $sort stages use temporary files on the server (as does AggAllowDiskUse).
+func (mog *Mog) AggCsvStream(w io.Writer, fields []string, allowDiskUse bool) error {
+	opts := options.Aggregate()
+	if allowDiskUse { // false must not override AggAllowDiskUse
+		opts.SetAllowDiskUse(true)
+	}
+	aggOpts := mog.aggOptions()
+	var cursor *mongo.Cursor
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			var err error
+			cursor, err = mog.collection.Aggregate(mog.ctx, mog.notDeletedPipeline(mog.AggPipeline), aggOpts, opts)
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(mog.ctx)
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Write(fields)
+	record := make([]string, len(fields))
+	for cursor.Next(mog.ctx) {
+		for i, fld := range fields {
+			value, err := cursor.Current.LookupErr(strings.Split(fld, ".")...)
+			if err != nil {
+				record[i] = ""
+				continue
+			}
+			record[i] = CsvValue(value)
+		}
+		if err = csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	if err = cursor.Err(); err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
+
+// CsvValue formats a bson value for csv output.
+// Strings are written as is, numbers in plain decimal, dates as RFC3339, ObjectIDs as hex.
+// Null values are written as empty strings, other types as extended JSON.
+func CsvValue(value bson.RawValue) string {
+	switch value.Type {
+	case bsontype.String:
+		return value.StringValue()
+	case bsontype.Int32:
+		return strconv.FormatInt(int64(value.Int32()), 10)
+	case bsontype.Int64:
+		return strconv.FormatInt(value.Int64(), 10)
+	case bsontype.Double:
+		return strconv.FormatFloat(value.Double(), 'f', -1, 64)
+	case bsontype.Boolean:
+		return strconv.FormatBool(value.Boolean())
+	case bsontype.DateTime:
+		return value.Time().UTC().Format(time.RFC3339)
+	case bsontype.ObjectID:
+		return value.ObjectID().Hex()
+	case bsontype.Null, bsontype.Undefined:
+		return ""
+	}
+	return value.String()
+}
+
 // --- Aggregate Methods ----------------------------------------------------
 
 // AggStart makes new AggPipeline slice.
@@ -424,6 +1860,12 @@ func (mog *Mog) AggStart() {
 	mog.AggPipeline = make([]bson.M, 0, 10)
 }
 
+// AggAllowDiskUse lets the next Agg.. run write temporary files when stages such as $sort and $group
+// exceed the server's memory limit. Resets after execution.
+func (mog *Mog) AggAllowDiskUse() {
+	mog.allowDiskUse = true
+}
+
 // AggStage adds a stage to AggPipeline.
 // Parm "op" is operation ("match", "group", etc.)
 // Parm "opParms" is map of values used for the operations.
@@ -434,6 +1876,43 @@ func (mog *Mog) AggStage(op string, opParms bson.M) {
 	mog.AggPipeline = append(mog.AggPipeline, stage)
 }
 
+// AggStageD works like AggStage except opParms is ordered (bson.D), for operations where key order matters,
+// such as $sort or $group accumulators built by hand.
+// Ex: AggStageD("sort", bson.D{{Key: "st", Value: 1}, {Key: "city", Value: -1}})
+func (mog *Mog) AggStageD(op string, opParms bson.D) {
+	mog.AggPipeline = append(mog.AggPipeline, bson.M{"$" + op: opParms})
+}
+
+// AggAddStage adds stage (an ordered doc such as bson.D{{Key: "$sort", Value: bson.D{...}}}) to AggPipeline.
+// Ordering of values within the stage is kept. Returns error if stage does not have exactly 1 key (the operation).
+func (mog *Mog) AggAddStage(stage bson.D) error {
+	if len(stage) != 1 {
+		return errors.New("pipeline stage must have exactly 1 key, has " + strconv.Itoa(len(stage)))
+	}
+	mog.AggPipeline = append(mog.AggPipeline, bson.M{stage[0].Key: stage[0].Value})
+	return nil
+}
+
+// AggMatch adds a $match stage to AggPipeline, only docs matching criteria are passed to the next stage.
+// Nil criteria matches all docs.
+// Ex: AggMatch(bson.M{"st": "NV"})
+func (mog *Mog) AggMatch(criteria interface{}) {
+	if criteria == nil {
+		criteria = bson.M{}
+	}
+	mog.AggPipeline = append(mog.AggPipeline, bson.M{"$match": criteria})
+}
+
+// AggLimit adds a $limit stage to AggPipeline, only the 1st n docs are passed to the next stage.
+func (mog *Mog) AggLimit(n int64) {
+	mog.AggPipeline = append(mog.AggPipeline, bson.M{"$limit": n})
+}
+
+// AggSkip adds a $skip stage to AggPipeline, the 1st n docs are not passed to the next stage.
+func (mog *Mog) AggSkip(n int64) {
+	mog.AggPipeline = append(mog.AggPipeline, bson.M{"$skip": n})
+}
+
 // AggLookupId adds $lookup and $unwind stages to AggPipeline.
 // ForeignField is assumed to be "_id".
 // The joined sub-document field name defaults to "fromCollection", to override include parm "asName".
@@ -450,6 +1929,130 @@ func (mog *Mog) AggLookupId(fromCollection, localField string, asName ...string)
 	mog.AggPipeline = append(mog.AggPipeline, bson.M{"$unwind": "$" + asName[0]})
 }
 
+// AggLookupMany adds a $lookup stage to AggPipeline joining docs of fromCollection whose foreignField matches localField.
+// Unlike AggLookupId, there is no $unwind, the joined docs are kept as array asName (empty if none), one-to-many.
+// Ex: AggLookupMany("property", "_id", "location_id", "properties") run on location collection.
+func (mog *Mog) AggLookupMany(fromCollection, localField, foreignField, asName string) {
+	mog.AggStage("lookup", bson.M{
+		"from":         fromCollection,
+		"localField":   localField,
+		"foreignField": foreignField,
+		"as":           asName,
+	})
+}
+
+// AggLookupPipeline adds a $lookup stage to AggPipeline using the pipeline form, joined docs of collection from
+// are those output by pipeline, added to each doc as array asName.
+// Parm "let" defines variables (referenced as "$$name" in pipeline) from the input doc's fields, nil if none.
+// Use $expr in a $match stage of pipeline to compare joined doc fields with variables.
+// Ex: AggLookupPipeline("property", bson.M{"loc": "$_id"},
+//
+//	[]bson.M{{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$location_id", "$$loc"}}}}, {"$project": bson.M{"city": 1}}},
+//	"properties")
+func (mog *Mog) AggLookupPipeline(from string, let bson.M, pipeline []bson.M, asName string) {
+	lookupParms := bson.M{
+		"from":     from,
+		"pipeline": pipeline,
+		"as":       asName,
+	}
+	if let != nil {
+		lookupParms["let"] = let
+	}
+	mog.AggStage("lookup", lookupParms)
+}
+
+// AggUnwind adds an $unwind stage to AggPipeline, outputting a doc for each element of array field path.
+// Parm "path" is the field name (without "$").
+// If preserveEmpty is true, docs where path is missing, null or an empty array are output unchanged (otherwise dropped).
+// If includeIndexField is given, each output doc has a field by that name holding the element's array index.
+// Ex: AggUnwind("notes", true, "note_index")
+func (mog *Mog) AggUnwind(path string, preserveEmpty bool, includeIndexField ...string) {
+	unwindParms := bson.M{"path": "$" + path}
+	if preserveEmpty {
+		unwindParms["preserveNullAndEmptyArrays"] = true
+	}
+	if len(includeIndexField) > 0 {
+		unwindParms["includeArrayIndex"] = includeIndexField[0]
+	}
+	mog.AggStage("unwind", unwindParms)
+}
+
+// AggFacet adds a $facet stage to AggPipeline, running each sub-pipeline of facets on the same input docs.
+// A single doc is output having a field for each facet name holding the array of that sub-pipeline's results.
+// Sub-pipelines can be built using the Agg.. methods of another Mog, then passing its AggPipeline.
+// Ex: byCity := NewMog(ctx, nil); byCity.AggStart(); byCity.AggTotal("city")
+//
+//	AggFacet(map[string][]bson.M{"by_city": byCity.AggPipeline, "total": {{"$count": "count"}}})
+func (mog *Mog) AggFacet(facets map[string][]bson.M) {
+	facetParms := make(bson.M, len(facets))
+	for name, pipeline := range facets {
+		facetParms[name] = pipeline
+	}
+	mog.AggStage("facet", facetParms)
+}
+
+// AggBucket adds a $bucket stage to AggPipeline, grouping docs into ranges of field groupBy (a histogram).
+// Parm "boundaries" are the ascending range limits, each bucket includes its lower limit and excludes its upper.
+// Parm "defaultBucket", if not nil, is the _id of the bucket holding docs outside the boundaries (otherwise an error).
+// Parm "output", if not nil, defines the fields of each bucket doc, default is count.
+// Ex: AggBucket("sum_fld1", []interface{}{0, 10, 20}, "other", nil) outputs docs {_id: 0, count: n}, {_id: 10, count: n}
+func (mog *Mog) AggBucket(groupBy string, boundaries []interface{}, defaultBucket interface{}, output bson.M) {
+	bucketParms := bson.M{
+		"groupBy":    "$" + groupBy,
+		"boundaries": boundaries,
+	}
+	if defaultBucket != nil {
+		bucketParms["default"] = defaultBucket
+	}
+	if output != nil {
+		bucketParms["output"] = output
+	}
+	mog.AggStage("bucket", bucketParms)
+}
+
+// AggBucketAuto adds a $bucketAuto stage to AggPipeline, grouping docs into the number of buckets requested
+// with boundaries chosen so docs are evenly distributed.
+// Each bucket doc has _id {min, max} and count.
+func (mog *Mog) AggBucketAuto(groupBy string, buckets int) {
+	mog.AggStage("bucketAuto", bson.M{"groupBy": "$" + groupBy, "buckets": buckets})
+}
+
+// AggGraphLookup adds a $graphLookup stage to AggPipeline, recursively joining docs of collection from (tree/graph traversal).
+// Starting with the value of field startWith, docs in from whose connectToField matches are joined, then docs whose
+// connectToField matches their connectFromField, and so on. All joined docs are added to each doc as array asName.
+// Parm "maxDepth" limits recursion, 0 joins direct matches only, -1 is unlimited.
+// Ex: AggGraphLookup("employee", "manager_id", "manager_id", "_id", "chain_of_command", -1)
+func (mog *Mog) AggGraphLookup(from, startWith, connectFromField, connectToField, asName string, maxDepth int) {
+	graphParms := bson.M{
+		"from":             from,
+		"startWith":        "$" + startWith,
+		"connectFromField": connectFromField,
+		"connectToField":   connectToField,
+		"as":               asName,
+	}
+	if maxDepth >= 0 {
+		graphParms["maxDepth"] = maxDepth
+	}
+	mog.AggStage("graphLookup", graphParms)
+}
+
+// AggChildRollup adds stages to AggPipeline that total sumField of child docs for each parent doc.
+// Mog's collection holds the parent docs, childCollection holds the child docs.
+// Parm "parentLocalField" is the field in child docs containing the parent's _id.
+// The total is added to each parent doc as outputField. Parents without children total 0.
+// Ex: AggChildRollup("property", "location_id", "sum_fld1", "tot_sum_fld1") run on location collection.
+func (mog *Mog) AggChildRollup(childCollection, parentLocalField, sumField, outputField string) {
+	const children = "_rollup_children"
+	mog.AggStage("lookup", bson.M{
+		"from":         childCollection,
+		"localField":   "_id",
+		"foreignField": parentLocalField,
+		"as":           children,
+	})
+	mog.AggStage("addFields", bson.M{outputField: bson.M{"$sum": "$" + children + "." + sumField}})
+	mog.AggStage("project", bson.M{children: 0})
+}
+
 // AggKeep works basically the same as Keep method (used for Find operations).
 // It determines what fields are kept and passed to the next stage of the pipeline.
 // A $project stage is added to AggPipeline.
@@ -496,21 +2099,301 @@ func (mog *Mog) AggTotal(groupBy string, sumFlds ...string) {
 	mog.AggPipeline = append(mog.AggPipeline, stage)
 }
 
+// AggAddFields adds an $addFields stage to AggPipeline, fields are added to each doc (or replaced if they exist).
+// Values are expressions: "$fld" references, literals, or helpers Concat, Multiply, Cond.
+// Ex: AggAddFields(bson.M{"full_address": Concat("$address", ", ", "$city"), "double": Multiply("$sum_fld1", 2)})
+func (mog *Mog) AggAddFields(fields bson.M) {
+	mog.AggStage("addFields", fields)
+}
+
+// Concat returns a $concat expression joining strings, parts are "$fld" references or literals.
+func Concat(parts ...interface{}) bson.M {
+	return bson.M{"$concat": parts}
+}
+
+// Multiply returns a $multiply expression, the product of numbers, parts are "$fld" references or literals.
+func Multiply(parts ...interface{}) bson.M {
+	return bson.M{"$multiply": parts}
+}
+
+// Cond returns a $cond expression with value thenValue if ifExpr is true, otherwise elseValue.
+// Ex: Cond(bson.M{"$gte": bson.A{"$sum_fld1", 10}}, "high", "low")
+func Cond(ifExpr, thenValue, elseValue interface{}) bson.M {
+	return bson.M{"$cond": bson.A{ifExpr, thenValue, elseValue}}
+}
+
+// AggReplaceRoot adds a $replaceRoot stage to AggPipeline, each doc is replaced by newRootExpr.
+// A string is a field name (with or without "$") whose sub-document becomes the doc, such as the result of AggLookupId.
+// Other values are expressions, ex: bson.M{"$mergeObjects": bson.A{"$location", bson.M{"prop_id": "$_id"}}}
+// Docs where the new root is missing or not a document cause an error.
+func (mog *Mog) AggReplaceRoot(newRootExpr interface{}) {
+	if fld, ok := newRootExpr.(string); ok && !strings.HasPrefix(fld, "$") {
+		newRootExpr = "$" + fld
+	}
+	mog.AggStage("replaceRoot", bson.M{"newRoot": newRootExpr})
+}
+
+// AggSample adds a $sample stage to AggPipeline, n randomly selected docs are passed to the next stage.
+func (mog *Mog) AggSample(n int) {
+	mog.AggStage("sample", bson.M{"size": n})
+}
+
+// AggCountStage adds a $count stage to AggPipeline, outputting a single doc with field fieldName holding
+// the number of docs input to the stage. See AggRunCount.
+func (mog *Mog) AggCountStage(fieldName string) {
+	mog.AggPipeline = append(mog.AggPipeline, bson.M{"$count": fieldName})
+}
+
+// AggOut adds an $out stage to AggPipeline, the pipeline's results replace the contents of targetCollection.
+// The target is in mog's database unless targetDb is given. Must be the last stage.
+// Run using AggRun (Next returns false, there are no results to iterate) or AggRunAll.
+// Ex: AggOut("property_summary")
+func (mog *Mog) AggOut(targetCollection string, targetDb ...string) {
+	var target interface{} = targetCollection
+	if len(targetDb) > 0 {
+		target = bson.M{"db": targetDb[0], "coll": targetCollection}
+	}
+	mog.AggPipeline = append(mog.AggPipeline, bson.M{"$out": target})
+}
+
+// AggMerge adds a $merge stage to AggPipeline, the pipeline's results are merged into collection target
+// (in mog's database), maintaining it incrementally instead of replacing it like AggOut. Must be the last stage.
+// Parm "onFields" identify the matching target doc, nil uses _id. Target must have a unique index on onFields.
+// Parm "whenMatched" is "replace", "keepExisting", "merge" (default if "") or "fail".
+// Parm "whenNotMatched" is "insert" (default if ""), "discard" or "fail".
+// Ex: AggMerge("property_summary", []string{"st"}, "replace", "insert")
+func (mog *Mog) AggMerge(target string, onFields []string, whenMatched, whenNotMatched string) {
+	mergeParms := bson.M{"into": target}
+	if len(onFields) > 0 {
+		mergeParms["on"] = onFields
+	}
+	if whenMatched != "" {
+		mergeParms["whenMatched"] = whenMatched
+	}
+	if whenNotMatched != "" {
+		mergeParms["whenNotMatched"] = whenNotMatched
+	}
+	mog.AggStage("merge", mergeParms)
+}
+
+// AggWindow adds a $setWindowFields stage to AggPipeline, adding output fields computed over a window of docs
+// (running totals, rank, moving averages, etc.). Requires MongoDB 5.0+.
+// Parm "partitionBy" is the field grouping docs into partitions, "" for one partition.
+// Parm "sortBy" is the field ordering docs within each partition, "-" prefix for descending, "" for no order
+// (RunningTotal, Rank and MovingAvg require an order).
+// Parm "output" maps new field names to window operators, such as RunningTotal, Rank and MovingAvg.
+// Ex: AggWindow("st", "date_added", bson.M{"running_tot": RunningTotal("sum_fld1"), "rank": Rank()})
+func (mog *Mog) AggWindow(partitionBy, sortBy string, output bson.M) {
+	windowParms := bson.M{"output": output}
+	if sortBy != "" {
+		windowParms["sortBy"] = CreateSortOrder([]string{sortBy})
+	}
+	if partitionBy != "" {
+		windowParms["partitionBy"] = "$" + partitionBy
+	}
+	mog.AggStage("setWindowFields", windowParms)
+}
+
+// RunningTotal returns an AggWindow output, the sum of field from the 1st doc of the partition thru the current doc.
+func RunningTotal(field string) bson.M {
+	return bson.M{"$sum": "$" + field, "window": bson.M{"documents": bson.A{"unbounded", "current"}}}
+}
+
+// Rank returns an AggWindow output, the doc's position in the partition by sortBy (ties get the same rank).
+func Rank() bson.M {
+	return bson.M{"$rank": bson.M{}}
+}
+
+// MovingAvg returns an AggWindow output, the average of field over the current doc and the docs-1 docs before it.
+func MovingAvg(field string, docs int) bson.M {
+	return bson.M{"$avg": "$" + field, "window": bson.M{"documents": bson.A{1 - docs, 0}}}
+}
+
+// AggDensify adds a $densify stage to AggPipeline, creating docs so field has a value every step between
+// its lowest and highest values (time-series gap filling). Created docs hold only field. Requires MongoDB 5.1+.
+// Parm "unit" is the time unit of a date field ("millisecond", "second", "minute", "hour", "day", "week", "month",
+// "quarter", "year"), "" for a numeric field.
+// Ex: AggDensify("reading_time", "hour", 1)
+func (mog *Mog) AggDensify(field, unit string, step int) {
+	rangeParms := bson.M{"step": step, "bounds": "full"}
+	if unit != "" {
+		rangeParms["unit"] = unit
+	}
+	mog.AggStage("densify", bson.M{"field": field, "range": rangeParms})
+}
+
+// AggFill adds a $fill stage to AggPipeline, setting null or missing fields (such as those of docs created by AggDensify).
+// Parm "output" maps each field to its fill: bson.M{"value": expr}, bson.M{"method": "locf"} (last value carried forward)
+// or bson.M{"method": "linear"}. Methods require sortFlds ("-" prefix for descending). Requires MongoDB 5.3+.
+// Ex: AggFill(bson.M{"temp": bson.M{"method": "linear"}, "source": bson.M{"value": "filled"}}, "reading_time")
+func (mog *Mog) AggFill(output bson.M, sortFlds ...string) {
+	fillParms := bson.M{"output": output}
+	if len(sortFlds) > 0 {
+		fillParms["sortBy"] = CreateSortOrder(sortFlds)
+	}
+	mog.AggStage("fill", fillParms)
+}
+
+// AggGroupByDate adds $group and $sort stages to AggPipeline, grouping docs by dateField truncated to granularity
+// ("day", "week", "month", "quarter", "year", "hour", etc.), output in date order. Requires MongoDB 5.0+.
+// Each group doc's _id is the start of the period. Parm "accumulators" define the other group fields, nil for count only.
+// Parm "timezone", if given, is the Olson name or offset used to determine period boundaries, default UTC.
+// Ex: AggGroupByDate("sold_at", "month", bson.M{"count": bson.M{"$sum": 1}, "total": bson.M{"$sum": "$price"}}, "America/Denver")
+func (mog *Mog) AggGroupByDate(dateField, granularity string, accumulators bson.M, timezone ...string) {
+	truncParms := bson.M{"date": "$" + dateField, "unit": granularity}
+	if len(timezone) > 0 {
+		truncParms["timezone"] = timezone[0]
+	}
+	groupParms := bson.M{"_id": bson.M{"$dateTrunc": truncParms}}
+	if accumulators == nil {
+		accumulators = bson.M{"count": bson.M{"$sum": 1}}
+	}
+	for fld, accumulator := range accumulators {
+		groupParms[fld] = accumulator
+	}
+	mog.AggStage("group", groupParms)
+	mog.AggSort("_id")
+}
+
+// AggTopNPerGroup adds stages to AggPipeline outputting the n docs with the highest sortField value for each
+// value of groupField (e.g. latest 3 properties per city), sorted by groupField then sortField descending.
+// Servers 6.0+ use the $topN accumulator, older servers $sort, $push and $slice (all docs of a group held in memory).
+// The server version is checked, returning error if it can not be determined (including when mog has no database).
+// Ex: AggTopNPerGroup("city", "date_added", 3)
+func (mog *Mog) AggTopNPerGroup(groupField, sortField string, n int) error {
+	major, err := mog.serverMajorVersion()
+	if err != nil {
+		return err
+	}
+	if major >= 6 {
+		mog.AggStage("group", bson.M{
+			"_id":  "$" + groupField,
+			"docs": bson.M{"$topN": bson.M{"n": n, "sortBy": bson.M{sortField: -1}, "output": "$$ROOT"}},
+		})
+	} else {
+		mog.AggSort("-" + sortField)
+		mog.AggStage("group", bson.M{"_id": "$" + groupField, "docs": bson.M{"$push": "$$ROOT"}})
+		mog.AggStage("project", bson.M{"docs": bson.M{"$slice": bson.A{"$docs", n}}})
+	}
+	mog.AggUnwind("docs", false)
+	mog.AggReplaceRoot("docs")
+	mog.AggSort(groupField, "-"+sortField)
+	return nil
+}
+
+// AggPivot adds stages to AggPipeline turning rows into columns (crosstab report).
+// A doc is output for each value of rowField (its _id), having a field for each value of colField
+// holding accumulator ("sum", "avg", "min", "max" or "count") of valueField for that row and column.
+// Parm "valueField" is ignored for "count". Docs are sorted by _id.
+// Docs missing colField (or having null) are totaled in a field named "null".
+// Ex: AggPivot("city", "st", "", "count") outputs {_id: "Wonder", MT: 2}, ...
+func (mog *Mog) AggPivot(rowField, colField, valueField, accumulator string) {
+	accumulator = strings.TrimPrefix(accumulator, "$")
+	value := bson.M{"$" + accumulator: "$" + valueField}
+	if accumulator == "count" {
+		value = bson.M{"$sum": 1}
+	}
+	mog.AggStage("group", bson.M{
+		"_id":   bson.M{"row": "$" + rowField, "col": "$" + colField},
+		"value": value,
+	})
+	mog.AggStage("group", bson.M{
+		"_id":  "$_id.row",
+		"cols": bson.M{"$push": bson.M{"k": bson.M{"$ifNull": bson.A{bson.M{"$toString": "$_id.col"}, "null"}}, "v": "$value"}},
+	})
+	mog.AggReplaceRoot(bson.M{"$mergeObjects": bson.A{bson.M{"_id": "$_id"}, bson.M{"$arrayToObject": "$cols"}}})
+	mog.AggSort("_id")
+}
+
+// AggCoalesce adds an $addFields stage to AggPipeline setting outputField to the first non-null value of fields.
+// Ex: AggCoalesce("display_name", "nickname", "name") uses nickname if present, otherwise name.
+func (mog *Mog) AggCoalesce(outputField string, fields ...string) {
+	if len(fields) == 0 {
+		return
+	}
+	var expr interface{} = "$" + fields[len(fields)-1]
+	for i := len(fields) - 2; i >= 0; i-- {
+		expr = bson.M{"$ifNull": bson.A{"$" + fields[i], expr}}
+	}
+	mog.AggStage("addFields", bson.M{outputField: expr})
+}
+
+// AggVectorSearch adds a $vectorSearch stage to AggPipeline, returning up to limit docs most similar to queryVector.
+// Must be the first stage of the pipeline and requires an Atlas vector search index (indexName) on field path.
+// Parm "numCandidates" is the number of nearest neighbors considered, typically 10 to 20 times limit.
+// Use an $addFields/$project stage with bson.M{"$meta": "vectorSearchScore"} to get each doc's similarity score.
+// Ex: AggVectorSearch("plot_index", "plot_embedding", embedding, 200, 10)
+func (mog *Mog) AggVectorSearch(indexName, path string, queryVector []float64, numCandidates, limit int) {
+	mog.AggStage("vectorSearch", bson.M{
+		"index":         indexName,
+		"path":          path,
+		"queryVector":   queryVector,
+		"numCandidates": numCandidates,
+		"limit":         limit,
+	})
+}
+
+// AggGeoNear adds a $geoNear stage to AggPipeline, docs sorted by distance (nearest first) from point lng, lat.
+// Parm "field" is the GeoJSON field to use, it must have a 2dsphere index.
+// Parm "distanceField" is the output field loaded with the distance in meters.
+// Parm "maxMeters", if > 0, excludes docs farther away.
+// $geoNear must be the first stage, an error is returned if AggPipeline already has stages.
+// Ex: AggGeoNear("location", -115.17, 36.11, "distance", 5000)
+func (mog *Mog) AggGeoNear(field string, lng, lat float64, distanceField string, maxMeters int) error {
+	if len(mog.AggPipeline) > 0 {
+		return errors.New("AggGeoNear must be the first stage of AggPipeline")
+	}
+	geoNearParms := bson.M{
+		"near":          bson.M{"type": "Point", "coordinates": bson.A{lng, lat}},
+		"key":           field,
+		"distanceField": distanceField,
+		"spherical":     true,
+	}
+	if maxMeters > 0 {
+		geoNearParms["maxDistance"] = maxMeters
+	}
+	mog.AggStage("geoNear", geoNearParms)
+	return nil
+}
+
 // AggRun executes the collection.Aggregate method using the AggPipeline.
 // Options can be set using optional mongo/options.AggregateOptions (see Mongo driver documentation).
 // The iterator, mog.iter, is loaded with the results cursor.
 // Use mog.Next() to iterate thru the results.
 // After complete, use mog.IterErr() to check for errors.
+// If the last stage is $out or $merge, the cursor is closed immediately and Next returns false.
 func (mog *Mog) AggRun(aggOptions ...*options.AggregateOptions) error {
 	opts := new(options.AggregateOptions)
 	if len(aggOptions) > 0 {
 		opts = aggOptions[0]
 	}
-	cursor, err := mog.collection.Aggregate(mog.ctx, mog.AggPipeline, opts)
+	aggOpts := mog.aggOptions()
+	var cursor *mongo.Cursor
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			var err error
+			cursor, err = mog.collection.Aggregate(mog.ctx, mog.notDeletedPipeline(mog.AggPipeline), aggOpts, opts)
+			return err
+		})
+	})
+	if err == nil && mog.aggWritesOutput() { // no results, docs written to output collection
+		cursor.Close(mog.ctx)
+		cursor = nil
+	}
 	mog.iter = cursor
+	mog.iterErr = err
 	return err
 }
 
+// aggWritesOutput returns true if the last stage of AggPipeline is $out or $merge.
+func (mog *Mog) aggWritesOutput() bool {
+	if len(mog.AggPipeline) == 0 {
+		return false
+	}
+	lastStage := mog.AggPipeline[len(mog.AggPipeline)-1]
+	return lastStage["$out"] != nil || lastStage["$merge"] != nil
+}
+
 // AggRunAll works like AggRun except all results are loaded into target.
 // Parm "target" should be pointer to slice.
 func (mog *Mog) AggRunAll(target interface{}, aggOptions ...*options.AggregateOptions) error {
@@ -518,21 +2401,159 @@ func (mog *Mog) AggRunAll(target interface{}, aggOptions ...*options.AggregateOp
 	if len(aggOptions) > 0 {
 		opts = aggOptions[0]
 	}
-	cursor, err := mog.collection.Aggregate(mog.ctx, mog.AggPipeline, opts)
-	err = cursor.All(mog.ctx, target)
-	return err
+	aggOpts := mog.aggOptions()
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			cursor, err := mog.collection.Aggregate(mog.ctx, mog.notDeletedPipeline(mog.AggPipeline), aggOpts, opts)
+			if err != nil {
+				return err
+			}
+			return cursor.All(mog.ctx, target)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return afterFindAll(target)
+}
+
+// AggRunCount executes the aggregation and returns the number of docs output by the pipeline.
+// If the last stage is not a $count stage (see AggCountStage), one is added for the run, AggPipeline is not changed.
+func (mog *Mog) AggRunCount(aggOptions ...*options.AggregateOptions) (int64, error) {
+	pipeline := mog.AggPipeline
+	countFld := "count"
+	if len(pipeline) > 0 && pipeline[len(pipeline)-1]["$count"] != nil {
+		countFld, _ = pipeline[len(pipeline)-1]["$count"].(string)
+	} else {
+		pipeline = append(pipeline[:len(pipeline):len(pipeline)], bson.M{"$count": countFld})
+	}
+	opts := new(options.AggregateOptions)
+	if len(aggOptions) > 0 {
+		opts = aggOptions[0]
+	}
+	aggOpts := mog.aggOptions()
+	var count int64
+	err := mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			cursor, err := mog.collection.Aggregate(mog.ctx, mog.notDeletedPipeline(pipeline), aggOpts, opts)
+			if err != nil {
+				return err
+			}
+			defer cursor.Close(mog.ctx)
+			if !cursor.Next(mog.ctx) {
+				return cursor.Err() // no docs to count
+			}
+			var ok bool
+			if count, ok = cursor.Current.Lookup(countFld).AsInt64OK(); !ok {
+				return errors.New("AggRunCount result has no numeric field " + countFld)
+			}
+			return nil
+		})
+	})
+	return count, err
+}
+
+// AggRunOn works like AggRunAll except the pipeline is run against collectionName.
+// Read preference and write concern set on mog also apply to collectionName.
+// The collection used by mog (see SetCollection) is not changed.
+// Allows one pipeline to be run against several collections (e.g. monthly collections).
+// Parm "docs" should be pointer to slice.
+func (mog *Mog) AggRunOn(collectionName string, docs interface{}, aggOptions ...*options.AggregateOptions) error {
+	opts := new(options.AggregateOptions)
+	if len(aggOptions) > 0 {
+		opts = aggOptions[0]
+	}
+	aggOpts := mog.aggOptions()
+	return mog.timed(mog.readTimeout, func() error {
+		return mog.guard(func() error {
+			cursor, err := mog.db.Collection(collectionName, mog.collectionOptions()).Aggregate(mog.ctx, mog.notDeletedPipeline(mog.AggPipeline), aggOpts, opts)
+			if err != nil {
+				return err
+			}
+			return cursor.All(mog.ctx, docs)
+		})
+	})
+}
+
+// AggCreateView creates a read-only view named viewName backed by the AggPipeline run on mog's collection.
+// The view can be queried like a collection (e.g. SetCollection(viewName) then Find).
+// The pipeline is evaluated each time the view is read.
+func (mog *Mog) AggCreateView(viewName string) error {
+	return mog.db.CreateView(mog.ctx, viewName, mog.collectionName, mog.AggPipeline)
 }
 
 // AggShowPipeline displays the aggregation pipeline stages(mog.AggPipeline).
 // Useful for debugging.
 func (mog *Mog) AggShowPipeline() {
 	fmt.Println("--- Aggregate Pipeline Stages ----------------------------")
-	for _, stage := range mog.AggPipeline {
-		fmt.Printf("%+v\n", stage)
+	if err := mog.AggWritePipeline(os.Stdout); err != nil {
+		fmt.Println(err)
 	}
 	fmt.Println()
 }
 
+// AggWritePipeline writes the aggregation pipeline stages (mog.AggPipeline) to w as pretty-printed extended JSON,
+// one stage after another. Useful for sending pipeline dumps to loggers or files.
+func (mog *Mog) AggWritePipeline(w io.Writer) error {
+	for _, stage := range mog.AggPipeline {
+		stageJSON, err := bson.MarshalExtJSONIndent(stage, false, false, "", "  ")
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(w, "%s\n", stageJSON); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AggPipelineString returns the aggregation pipeline stages as written by AggWritePipeline.
+// If a stage can not be converted to JSON, the error text is returned in its place.
+func (mog *Mog) AggPipelineString() string {
+	var sb strings.Builder
+	if err := mog.AggWritePipeline(&sb); err != nil {
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// AggPipelineJSON returns the aggregation pipeline (mog.AggPipeline) as a JSON array (relaxed extended JSON),
+// one stage per line. Useful for review or to run in other tools. See AggLoadPipeline.
+func (mog *Mog) AggPipelineJSON() (string, error) {
+	stages := make([]string, len(mog.AggPipeline))
+	for i, stage := range mog.AggPipeline {
+		stageJSON, err := bson.MarshalExtJSON(stage, false, false)
+		if err != nil {
+			return "", err
+		}
+		stages[i] = "  " + string(stageJSON)
+	}
+	return "[\n" + strings.Join(stages, ",\n") + "\n]", nil
+}
+
+// AggLoadPipeline replaces the aggregation pipeline (mog.AggPipeline) with stages from pipelineJSON,
+// a JSON array of stages (extended JSON, canonical or relaxed), such as from a config file or AggPipelineJSON.
+// Key order within stages is kept. If pipelineJSON is invalid, error is returned and AggPipeline is not changed.
+// Ex: AggLoadPipeline(`[{"$match": {"st": "NV"}}, {"$sort": {"city": 1}}]`)
+func (mog *Mog) AggLoadPipeline(pipelineJSON string) error {
+	var loaded struct {
+		Pipeline []bson.D `bson:"pipeline"`
+	}
+	err := bson.UnmarshalExtJSON([]byte(`{"pipeline": `+pipelineJSON+`}`), false, &loaded)
+	if err != nil {
+		return err
+	}
+	pipeline := make([]bson.M, len(loaded.Pipeline))
+	for i, stage := range loaded.Pipeline {
+		if len(stage) != 1 {
+			return errors.New("pipeline stage must have exactly 1 key, has " + strconv.Itoa(len(stage)))
+		}
+		pipeline[i] = bson.M{stage[0].Key: stage[0].Value}
+	}
+	mog.AggPipeline = pipeline
+	return nil
+}
+
 // CreateSorteOrder returns slice of bson elements (type bson.D) defining sort order.
 // Parm "keyFlds" are field names to be sorted in order of precedence.
 // Keys to be sorted in descending order begin with a minus sign "-".
@@ -2,12 +2,18 @@ package mog
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type m bson.M // for brevity
@@ -165,3 +171,936 @@ func Test_Mog(t *testing.T) {
 	}
 	fmt.Println("count successful")
 }
+
+func Test_RetryClassifier(t *testing.T) {
+	errSentinel := errors.New("sentinel")
+	mog1 := NewMog(context.Background(), nil)
+	mog1.SetRetries(3)
+
+	// default classifier does not retry app-level errors
+	attempts := 0
+	err := mog1.retry(func() error {
+		attempts++
+		return errSentinel
+	})
+	if err != errSentinel || attempts != 1 {
+		t.Fatal("Default Classifier Failed", err, attempts)
+	}
+
+	// network errors are retried for reads, not for writes unless labeled RetryableWriteError
+	networkErr := mongo.CommandError{Labels: []string{"NetworkError"}}
+	attempts = 0
+	mog1.retry(func() error { attempts++; return networkErr })
+	if attempts != 4 {
+		t.Fatal("Default Classifier Did Not Retry Read", attempts)
+	}
+	attempts = 0
+	mog1.retryWrite(func() error { attempts++; return networkErr })
+	if attempts != 1 {
+		t.Fatal("Default Write Classifier Retried Network Error", attempts)
+	}
+	attempts = 0
+	mog1.retryWrite(func() error {
+		attempts++
+		return mongo.CommandError{Labels: []string{"NetworkError", "RetryableWriteError"}}
+	})
+	if attempts != 4 {
+		t.Fatal("Default Write Classifier Did Not Retry Retryable Write", attempts)
+	}
+	if DefaultRetryClassifier(context.DeadlineExceeded) {
+		t.Fatal("Default Classifier Retries Expired Context")
+	}
+
+	// custom classifier retries sentinel error, op succeeds on 3rd attempt
+	mog1.SetRetryClassifier(func(err error) bool {
+		return errors.Is(err, errSentinel)
+	})
+	attempts = 0
+	err = mog1.retry(func() error {
+		attempts++
+		if attempts < 3 {
+			return errSentinel
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatal("Custom Classifier Failed", err, attempts)
+	}
+	fmt.Println("retry classifier successful")
+}
+
+// testMog connects to the local test server, drops collectionName in the demo db and returns Mog using it.
+// Call the returned func to disconnect.
+func testMog(t *testing.T, collectionName string) (*Mog, func()) {
+	mog1, disconnect, err := NewMogFromURI(context.Background(), "mongodb://localhost:27017", "demo", collectionName)
+	if err != nil {
+		t.Fatal("Mongo Connect Failed", err)
+	}
+	mog1.DropCollection()
+	return mog1, disconnect
+}
+
+// testReplicaSet skips the test if the test server is not a replica set member.
+func testReplicaSet(t *testing.T, mog1 *Mog) {
+	var hello struct {
+		SetName string `bson:"setName"`
+	}
+	mog1.db.RunCommand(mog1.ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if hello.SetName == "" {
+		t.Skip("test requires a replica set")
+	}
+}
+
+// testProps inserts a small set of properties used by many tests.
+func testProps(t *testing.T, mog1 *Mog) []Property {
+	props := []Property{
+		{Id: "p1", Address: "200 Willow Rd", City: "Wonder", St: "MT", LocationId: "7", DateAdded: "2018-03-11", SumFld1: 7, SumFld2: 12.50},
+		{Id: "p2", Address: "321 Angel Way", City: "Wonder", St: "MT", LocationId: "7", DateAdded: "2019-04-04", SumFld1: 10, SumFld2: 8.25},
+		{Id: "p3", Address: "1950 Hangover", City: "Las Vegas", St: "NV", LocationId: "10", DateAdded: "2017-07-29", SumFld1: 13, SumFld2: 19.25},
+	}
+	for _, prop := range props {
+		if err := mog1.Insert(prop); err != nil {
+			t.Fatal("Insert Test Props Failed", err)
+		}
+	}
+	return props
+}
+
+func Test_FindUntil(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	openCursors := func() int64 {
+		var status struct {
+			Metrics struct {
+				Cursor struct {
+					Open struct {
+						Total int64 `bson:"total"`
+					} `bson:"open"`
+				} `bson:"cursor"`
+			} `bson:"metrics"`
+		}
+		mog1.db.RunCommand(mog1.ctx, bson.D{{Key: "serverStatus", Value: 1}}).Decode(&status)
+		return status.Metrics.Cursor.Open.Total
+	}
+	before := openCursors()
+
+	var addresses []string
+	err := mog1.FindUntil(nil, func(raw bson.Raw) (bool, error) {
+		var prop Property
+		if err := bson.Unmarshal(raw, &prop); err != nil {
+			return true, err
+		}
+		addresses = append(addresses, prop.Address)
+		return len(addresses) == 2, nil
+	}, "address")
+	if err != nil || len(addresses) != 2 {
+		t.Fatal("FindUntil Failed", err, addresses)
+	}
+	if after := openCursors(); after != before {
+		t.Fatal("FindUntil Cursor Not Closed", before, after)
+	}
+	fmt.Println("findUntil successful")
+}
+
+func Test_Deduplicate(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.Insert(
+		Property{Id: "p4", Address: "200 Willow Rd", City: "Wonder", St: "MT"},
+		Property{Id: "p5", Address: "200 Willow Rd", City: "Wonder", St: "MT"},
+		Property{Id: "p6", Address: "1950 Hangover", City: "Las Vegas", St: "NV"},
+		m{"_id": "p7", "city": "Wonder"}, // no address, not duplicates
+		m{"_id": "p8", "city": "Wonder"},
+	)
+	removed, err := mog1.Deduplicate("address", "city")
+	if err != nil || removed != 3 {
+		t.Fatal("Deduplicate Failed", err, removed)
+	}
+	count, _ := mog1.Count(m{"address": "200 Willow Rd"})
+	if count != 1 {
+		t.Fatal("Deduplicate Left Duplicates", count)
+	}
+	var prop Property
+	if err = mog1.FindId("p1", &prop); err != nil {
+		t.Fatal("Deduplicate Removed Lowest Id", err)
+	}
+	if count, _ = mog1.Count(m{"address": m{"$exists": false}}); count != 2 {
+		t.Fatal("Deduplicate Removed Docs Missing Key Field", count)
+	}
+	fmt.Println("deduplicate successful")
+}
+
+func Test_WriteRateLimit(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	mog1.SetWriteRateLimit(10)
+	mog1.BulkStart(30)
+	for i := 0; i < 30; i++ {
+		mog1.BulkAddInsert(Property{Id: NewDocId(), City: "Wonder"})
+	}
+	start := time.Now()
+	count, err := mog1.BulkWrite()
+	elapsed := time.Since(start)
+	if err != nil || count != 30 {
+		t.Fatal("Rate Limited BulkWrite Failed", err, count)
+	}
+	// 1st chunk of 10 is written immediately, remaining 2 chunks wait 1 second each
+	if elapsed < 1800*time.Millisecond {
+		t.Fatal("Rate Limit Not Applied", elapsed)
+	}
+	fmt.Println("write rate limit successful", elapsed)
+}
+
+func Test_Delete(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	count, err := mog1.DeleteOne(m{"st": "MT"})
+	if err != nil || count != 1 {
+		t.Fatal("DeleteOne Failed", err, count)
+	}
+	if err = mog1.DeleteId("p3"); err != nil {
+		t.Fatal("DeleteId Failed", err)
+	}
+	if err = mog1.DeleteId("p3"); err != mongo.ErrNoDocuments {
+		t.Fatal("DeleteId Not Found Test Failed", err)
+	}
+	count, err = mog1.DeleteMany(bson.D{})
+	if err != nil || count != 1 {
+		t.Fatal("DeleteMany Failed", err, count)
+	}
+	fmt.Println("delete successful")
+}
+
+func Test_FindOneAndUpdate(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	// claim 1st unclaimed MT property by address, return doc before update
+	var prop Property
+	update := m{"$set": m{"location_id": "claimed"}}
+	err := mog1.FindOneAndUpdate(m{"st": "MT", "location_id": m{"$ne": "claimed"}}, update, &prop, "address")
+	if err != nil || prop.Id != "p1" || prop.LocationId != "7" {
+		t.Fatal("FindOneAndUpdate Before Failed", err, prop)
+	}
+	// next claim returns doc after update
+	mog1.ReturnAfter()
+	err = mog1.FindOneAndUpdate(m{"st": "MT", "location_id": m{"$ne": "claimed"}}, update, &prop, "address")
+	if err != nil || prop.Id != "p2" || prop.LocationId != "claimed" {
+		t.Fatal("FindOneAndUpdate After Failed", err, prop)
+	}
+	// upsert creates doc when none match
+	mog1.Upsert()
+	mog1.ReturnAfter()
+	err = mog1.FindOneAndUpdate(m{"_id": "p9"}, m{"$set": m{"city": "Newtown"}}, &prop)
+	if err != nil || prop.City != "Newtown" {
+		t.Fatal("FindOneAndUpdate Upsert Failed", err, prop)
+	}
+	fmt.Println("findOneAndUpdate successful")
+}
+
+func Test_FindOneAndDelete(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	var prop Property
+	err := mog1.FindOneAndDelete(m{"st": "MT"}, &prop, "-date_added")
+	if err != nil || prop.Id != "p2" {
+		t.Fatal("FindOneAndDelete Failed", err, prop)
+	}
+	if err = mog1.FindId("p2", &prop); err != mongo.ErrNoDocuments {
+		t.Fatal("FindOneAndDelete Did Not Delete", err)
+	}
+	if err = mog1.FindOneAndDelete(m{"st": "XX"}, &prop); err != mongo.ErrNoDocuments {
+		t.Fatal("FindOneAndDelete Not Found Test Failed", err)
+	}
+	fmt.Println("findOneAndDelete successful")
+}
+
+func Test_FindOneAndReplace(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	var oldProp Property
+	newProp := Property{Id: "p1", Address: "201 Willow Rd", City: "Wonder", St: "MT"}
+	err := mog1.FindOneAndReplace(m{"_id": "p1"}, newProp, &oldProp)
+	if err != nil || oldProp.Address != "200 Willow Rd" {
+		t.Fatal("FindOneAndReplace Failed", err, oldProp)
+	}
+	var prop Property
+	mog1.FindId("p1", &prop)
+	if prop.Address != "201 Willow Rd" {
+		t.Fatal("FindOneAndReplace Did Not Replace", prop)
+	}
+	mog1.Upsert()
+	mog1.ReturnAfter()
+	err = mog1.FindOneAndReplace(m{"_id": "p9"}, Property{Id: "p9", City: "Newtown"}, &prop)
+	if err != nil || prop.City != "Newtown" {
+		t.Fatal("FindOneAndReplace Upsert Failed", err, prop)
+	}
+	fmt.Println("findOneAndReplace successful")
+}
+
+func Test_Distinct(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	values, err := mog1.Distinct("city", nil)
+	if err != nil || len(values) != 2 {
+		t.Fatal("Distinct Failed", err, values)
+	}
+	var states []string
+	err = mog1.DistinctInto("st", m{"city": "Wonder"}, &states)
+	if err != nil || len(states) != 1 || states[0] != "MT" {
+		t.Fatal("DistinctInto Failed", err, states)
+	}
+	fmt.Println("distinct successful")
+}
+
+func Test_Exists(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	found, err := mog1.Exists(m{"st": "NV"})
+	if err != nil || !found {
+		t.Fatal("Exists Failed", err, found)
+	}
+	found, err = mog1.Exists(m{"st": "XX"})
+	if err != nil || found {
+		t.Fatal("Exists Not Found Test Failed", err, found)
+	}
+	fmt.Println("exists successful")
+}
+
+func Test_Save(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	prop := Property{Id: "s1", Address: "10 Main", City: "Wonder"}
+	if err := mog1.Save(prop); err != nil {
+		t.Fatal("Save Insert Failed", err)
+	}
+	prop.City = "Okobear"
+	if err := mog1.Save(prop); err != nil {
+		t.Fatal("Save Replace Failed", err)
+	}
+	var saved Property
+	mog1.FindId("s1", &saved)
+	count, _ := mog1.Count(bson.D{})
+	if saved.City != "Okobear" || count != 1 {
+		t.Fatal("Save Wrong Result", saved, count)
+	}
+	if err := mog1.Save(m{"city": "NoId"}); err == nil {
+		t.Fatal("Save Without Id Did Not Fail")
+	}
+	fmt.Println("save successful")
+}
+
+func Test_SetSkip(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	var props []Property
+	mog1.SetSkip(1)
+	mog1.SetLimit(1)
+	err := mog1.FindAll(nil, &props, "_id")
+	if err != nil || len(props) != 1 || props[0].Id != "p2" {
+		t.Fatal("SetSkip FindAll Failed", err, props)
+	}
+	// skip resets after execution
+	err = mog1.FindAll(nil, &props, "_id")
+	if err != nil || len(props) != 3 {
+		t.Fatal("SetSkip Not Reset", err, props)
+	}
+	var prop Property
+	mog1.SetSkip(2)
+	err = mog1.FindOne(bson.D{}, &prop, "_id")
+	if err != nil || prop.Id != "p3" {
+		t.Fatal("SetSkip FindOne Failed", err, prop)
+	}
+	fmt.Println("setSkip successful")
+}
+
+func Test_SetBatchSize(t *testing.T) {
+	mog1 := NewMog(context.Background(), nil)
+	mog1.SetBatchSize(500)
+	findOptions := mog1.findOptions(nil)
+	if findOptions.BatchSize == nil || *findOptions.BatchSize != 500 {
+		t.Fatal("SetBatchSize Find Failed", findOptions.BatchSize)
+	}
+	if findOptions = mog1.findOptions(nil); findOptions.BatchSize != nil {
+		t.Fatal("SetBatchSize Not Reset", *findOptions.BatchSize)
+	}
+	mog1.SetBatchSize(200)
+	aggOptions := mog1.aggOptions()
+	if aggOptions.BatchSize == nil || *aggOptions.BatchSize != 200 {
+		t.Fatal("SetBatchSize Agg Failed", aggOptions.BatchSize)
+	}
+	fmt.Println("setBatchSize successful")
+}
+
+func Test_SetHint(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	model := mongo.IndexModel{Keys: bson.D{{Key: "st", Value: 1}}, Options: options.Index().SetName("st_idx")}
+	if _, err := mog1.collection.Indexes().CreateOne(mog1.ctx, model); err != nil {
+		t.Fatal("Create Index Failed", err)
+	}
+	mog1.SetHint("st_idx")
+	count, err := mog1.Count(m{"st": "MT"})
+	if err != nil || count != 2 {
+		t.Fatal("SetHint Count Failed", err, count)
+	}
+	// hint naming a missing index fails, proving hint is passed to server
+	mog1.SetHint("missing_idx")
+	var props []Property
+	if err = mog1.FindAll(m{"st": "MT"}, &props); err == nil {
+		t.Fatal("SetHint Missing Index Did Not Fail")
+	}
+	// hint resets after execution
+	if err = mog1.FindAll(m{"st": "MT"}, &props); err != nil {
+		t.Fatal("SetHint Not Reset", err)
+	}
+	fmt.Println("setHint successful")
+}
+
+func Test_SetCollation(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.SetCollation("en", true)
+	count, err := mog1.Count(m{"city": "WONDER"})
+	if err != nil || count != 2 {
+		t.Fatal("SetCollation Count Failed", err, count)
+	}
+	var props []Property
+	mog1.SetCollation("en", true)
+	err = mog1.FindAll(m{"city": "las vegas"}, &props)
+	if err != nil || len(props) != 1 {
+		t.Fatal("SetCollation FindAll Failed", err, props)
+	}
+	// collation resets after execution
+	count, _ = mog1.Count(m{"city": "WONDER"})
+	if count != 0 {
+		t.Fatal("SetCollation Not Reset", count)
+	}
+	fmt.Println("setCollation successful")
+}
+
+func Test_SetMaxTime(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	// $where with sleep exceeds 10 ms limit
+	var props []Property
+	mog1.SetMaxTime(10 * time.Millisecond)
+	err := mog1.FindAll(m{"$where": "sleep(100) || true"}, &props)
+	if err == nil {
+		t.Fatal("SetMaxTime Did Not Limit Query")
+	}
+	// max time resets after execution
+	if err = mog1.FindAll(nil, &props); err != nil || len(props) != 3 {
+		t.Fatal("SetMaxTime Not Reset", err)
+	}
+	fmt.Println("setMaxTime successful")
+}
+
+func Test_SetReadPreference(t *testing.T) {
+	mog1 := NewMog(context.Background(), nil)
+	if err := mog1.SetReadPreference("bogus"); err == nil {
+		t.Fatal("SetReadPreference Invalid Mode Did Not Fail")
+	}
+	if err := mog1.SetReadPreference("secondaryPreferred"); err != nil {
+		t.Fatal("SetReadPreference Failed", err)
+	}
+	if mog1.readPref.Mode() != readpref.SecondaryPreferredMode {
+		t.Fatal("SetReadPreference Wrong Mode", mog1.readPref.Mode())
+	}
+	fmt.Println("setReadPreference successful")
+}
+
+func Test_SetWriteConcern(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	if err := mog1.SetWriteConcern(1.5, false, 0); err == nil {
+		t.Fatal("SetWriteConcern Invalid W Did Not Fail")
+	}
+	if err := mog1.SetWriteConcern(1, true, 2*time.Second); err != nil {
+		t.Fatal("SetWriteConcern Failed", err)
+	}
+	if err := mog1.Insert(Property{Id: "w1"}); err != nil {
+		t.Fatal("Insert With Write Concern Failed", err)
+	}
+	// w:0 writes are unacknowledged
+	mog1.SetWriteConcern(0, false, 0)
+	if err := mog1.Insert(Property{Id: "w2"}); err != nil {
+		t.Fatal("Unacknowledged Insert Failed", err)
+	}
+	fmt.Println("setWriteConcern successful")
+}
+
+func Test_SetReadConcern(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	if err := mog1.SetReadConcern("bogus"); err == nil {
+		t.Fatal("SetReadConcern Invalid Level Did Not Fail")
+	}
+	if err := mog1.SetReadConcern("local"); err != nil {
+		t.Fatal("SetReadConcern Failed", err)
+	}
+	count, err := mog1.Count(bson.D{})
+	if err != nil || count != 3 {
+		t.Fatal("Count With Read Concern Failed", err, count)
+	}
+	fmt.Println("setReadConcern successful")
+}
+
+func Test_FindError(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	err := mog1.Find(m{"$bogus": 1})
+	if err == nil {
+		t.Fatal("Find Bad Criteria Did Not Fail")
+	}
+	var prop Property
+	if mog1.Next(&prop) {
+		t.Fatal("Next After Failed Find Returned True")
+	}
+	if mog1.IterErr() != err {
+		t.Fatal("IterErr Not Set By Find", mog1.IterErr())
+	}
+	fmt.Println("find error successful")
+}
+
+type testLogger struct {
+	errors   []string
+	messages []string
+}
+
+func (l *testLogger) Printf(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+func (l *testLogger) Error(msg string, err error) {
+	l.errors = append(l.errors, msg)
+}
+
+func Test_SetLogger(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	mog1.Insert(m{"_id": "bad", "sum_fld1": "not a number"})
+
+	logger := new(testLogger)
+	mog1.SetLogger(logger)
+	mog1.Find(nil)
+	var prop Property
+	for mog1.Next(&prop) {
+	}
+	if mog1.IterErr() == nil || len(logger.errors) != 1 {
+		t.Fatal("SetLogger Did Not Receive Decode Error", mog1.IterErr(), logger.errors)
+	}
+	fmt.Println("setLogger successful")
+}
+
+// run with -race to verify clones are independent
+func Test_Clone(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.Keep("address")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(limit int64) {
+			defer wg.Done()
+			mogReq := mog1.Clone()
+			mogReq.SetLimit(limit)
+			var props []Property
+			if err := mogReq.FindAll(nil, &props, "_id"); err != nil {
+				errs <- err
+				return
+			}
+			if int64(len(props)) != limit || props[0].City != "" {
+				errs <- fmt.Errorf("limit %d returned %d props %+v", limit, len(props), props[0])
+			}
+		}(int64(i%3 + 1))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal("Clone Failed", err)
+	}
+	if mog1.limit != 0 {
+		t.Fatal("Clone Changed Original", mog1.limit)
+	}
+	fmt.Println("clone successful")
+}
+
+func Test_SetDatabase(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	if err := mog1.SetDatabase("demo_tenant"); err != nil {
+		t.Fatal("SetDatabase Failed", err)
+	}
+	mog1.DropCollection()
+	found, err := mog1.Exists(m{"st": "NV"})
+	if err != nil || found {
+		t.Fatal("SetDatabase Failed", err, found)
+	}
+	mog1.SetDatabase("demo")
+	found, err = mog1.Exists(m{"st": "NV"})
+	if err != nil || !found {
+		t.Fatal("SetDatabase Back Failed", err, found)
+	}
+	if err = NewMog(context.Background(), nil).SetDatabase("demo"); err == nil {
+		t.Fatal("SetDatabase Without Database Did Not Fail")
+	}
+	fmt.Println("set database successful")
+}
+
+func Test_FindRandom(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	var props []Property
+	mog1.Keep("city")
+	if err := mog1.FindRandom(2, &props); err != nil || len(props) != 2 || props[0].City == "" || props[0].St != "" {
+		t.Fatal("FindRandom Failed", err, props)
+	}
+	if err := mog1.FindRandom(5, &props); err != nil || len(props) != 3 {
+		t.Fatal("FindRandom More Than Count Failed", err, len(props))
+	}
+	mog1.AggStart()
+	mog1.AggMatch(bson.M{"st": "MT"})
+	mog1.AggSample(1)
+	if err := mog1.AggRunAll(&props); err != nil || len(props) != 1 || props[0].Id == "p3" {
+		t.Fatal("AggSample Failed", err, props)
+	}
+	fmt.Println("findRandom successful")
+}
+
+func Test_BulkDelete(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.BulkStart(4)
+	mog1.BulkAddInsert(Property{Id: "p4", City: "Wonder", St: "MT"})
+	mog1.BulkAddUpdate(m{"_id": "p3"}, m{"$set": m{"city": "Reno"}})
+	mog1.BulkAddDeleteOne(m{"st": "MT"})
+	mog1.BulkAddDelete(m{"city": "Wonder"})
+	count, err := mog1.BulkWrite()
+	if err != nil || count != 5 {
+		t.Fatal("BulkWrite With Deletes Failed", err, count)
+	}
+	var props []Property
+	if err = mog1.FindAll(nil, &props); err != nil || len(props) != 1 || props[0].City != "Reno" {
+		t.Fatal("BulkDelete Wrong Results", err, props)
+	}
+	fmt.Println("bulkDelete successful")
+}
+
+func Test_BulkReplace(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.BulkStart(4)
+	mog1.BulkAddUpdateOne(m{"st": "MT"}, m{"$set": m{"notes": []string{"first"}}}, false)
+	mog1.BulkAddUpdateOne(m{"_id": "p4"}, m{"$set": m{"city": "Butte"}}, true)
+	mog1.BulkAddReplace(m{"_id": "p3"}, Property{Id: "p3", City: "Reno", St: "NV"}, false)
+	mog1.BulkAddReplace(m{"_id": "p5"}, Property{Id: "p5", City: "Elko", St: "NV"}, true)
+	count, err := mog1.BulkWrite()
+	if err != nil || count != 4 {
+		t.Fatal("BulkWrite With Replace Failed", err, count)
+	}
+	if n, _ := mog1.Count(m{"notes": "first"}); n != 1 {
+		t.Fatal("BulkAddUpdateOne Updated More Than 1", n)
+	}
+	var prop Property
+	if err = mog1.FindId("p3", &prop); err != nil || prop.City != "Reno" || prop.Address != "" {
+		t.Fatal("BulkAddReplace Failed", err, prop)
+	}
+	if n, _ := mog1.Count(m{"_id": m{"$in": []string{"p4", "p5"}}}); n != 2 {
+		t.Fatal("Bulk Upserts Failed", n)
+	}
+	fmt.Println("bulkReplace successful")
+}
+
+func Test_BulkUnordered(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	addWrites := func() {
+		mog1.BulkStart(4)
+		mog1.BulkAddInsert(Property{Id: "p1"}) // duplicate
+		mog1.BulkAddInsert(Property{Id: "p4"})
+		mog1.BulkAddInsert(Property{Id: "p2"}) // duplicate
+		mog1.BulkAddInsert(Property{Id: "p5"})
+	}
+	addWrites()
+	count, err := mog1.BulkWrite()
+	var bulkErrors *BulkErrors
+	if !errors.As(err, &bulkErrors) || count != 0 || len(bulkErrors.OpErrors) != 1 || bulkErrors.OpErrors[0].Index != 0 {
+		t.Fatal("Ordered BulkWrite Errors Failed", err, count)
+	}
+	addWrites()
+	mog1.BulkUnordered()
+	count, err = mog1.BulkWrite()
+	if !errors.As(err, &bulkErrors) || count != 2 || len(bulkErrors.OpErrors) != 2 {
+		t.Fatal("Unordered BulkWrite Failed", err, count)
+	}
+	if bulkErrors.OpErrors[1].Index != 2 || bulkErrors.OpErrors[1].Code != 11000 {
+		t.Fatal("Unordered BulkWrite Wrong Error", bulkErrors.OpErrors)
+	}
+	fmt.Println("bulkUnordered successful")
+}
+
+func Test_BulkAutoFlush(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	mog1.BulkAutoFlush(10)
+	mog1.BulkStart(10)
+	for i := 0; i < 25; i++ {
+		mog1.BulkAddInsert(Property{Id: strconv.Itoa(i), City: "Wonder"})
+	}
+	if count, _ := mog1.Count(m{}); count != 20 || len(mog1.bulkWrites) != 5 {
+		t.Fatal("BulkAutoFlush Did Not Flush", count, len(mog1.bulkWrites))
+	}
+	count, err := mog1.BulkWrite()
+	if err != nil || count != 25 {
+		t.Fatal("BulkAutoFlush BulkWrite Failed", err, count)
+	}
+
+	mog1.BulkStart(10)
+	for i := 20; i < 1045; i++ { // 20 - 24 are duplicates, 1st flush fails at entry 0
+		mog1.BulkAddInsert(Property{Id: strconv.Itoa(i), City: "Wonder"})
+	}
+	if mog1.BulkErr() == nil || len(mog1.bulkWrites) != 0 {
+		t.Fatal("BulkAutoFlush Kept Entries After Failed Flush", mog1.BulkErr(), len(mog1.bulkWrites))
+	}
+	count, err = mog1.BulkWrite()
+	var bulkErrors *BulkErrors
+	if !errors.As(err, &bulkErrors) || count != 0 || bulkErrors.OpErrors[0].Index != 0 {
+		t.Fatal("BulkAutoFlush Error Not Returned", err, count)
+	}
+	if n, _ := mog1.Count(m{}); n != 25 {
+		t.Fatal("BulkAutoFlush Wrote After Failed Flush", n)
+	}
+	fmt.Println("bulkAutoFlush successful")
+}
+
+func Test_BulkWriteDetailed(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.BulkStart(5)
+	mog1.BulkAddInsert(Property{Id: "p4"})
+	mog1.BulkAddUpdate(m{"st": "MT"}, m{"$set": m{"city": "Wonder"}}) // matched, not modified
+	mog1.BulkAddReplace(m{"_id": "p5"}, Property{Id: "p5"}, true)
+	mog1.BulkAddDeleteOne(m{"_id": "p3"})
+	mog1.BulkUnordered()
+	mog1.BulkAddInsert(Property{Id: "p1"}) // duplicate
+	result, err := mog1.BulkWriteDetailed()
+	if err == nil || len(result.OpErrors) != 1 || result.OpErrors[0].Index != 4 {
+		t.Fatal("BulkWriteDetailed Error Failed", err, result)
+	}
+	if result.Inserted != 1 || result.Matched != 2 || result.Modified != 0 || result.Deleted != 1 || result.Upserted != 1 {
+		t.Fatal("BulkWriteDetailed Wrong Counts", result)
+	}
+	if result.UpsertedIds[2] != "p5" || result.Total() != 3 {
+		t.Fatal("BulkWriteDetailed Wrong Upserted Ids", result.UpsertedIds, result.Total())
+	}
+	fmt.Println("bulkWriteDetailed successful")
+}
+
+func Test_BulkFromChannel(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	ch := make(chan interface{})
+	go func() {
+		defer close(ch)
+		for i := 0; i < 25; i++ {
+			ch <- Property{Id: strconv.Itoa(i), City: "Wonder"}
+		}
+	}()
+	count, err := mog1.BulkFromChannel(ch, 10)
+	if err != nil || count != 25 {
+		t.Fatal("BulkFromChannel Failed", err, count)
+	}
+	if n, _ := mog1.Count(m{"city": "Wonder"}); n != 25 {
+		t.Fatal("BulkFromChannel Wrong Count", n)
+	}
+
+	ch = make(chan interface{}, 3)
+	ch <- Property{Id: "30"}
+	ch <- Property{Id: "3"} // duplicate
+	ch <- Property{Id: "31"}
+	close(ch)
+	count, err = mog1.BulkFromChannel(ch, 10)
+	var bulkErrors *BulkErrors
+	if !errors.As(err, &bulkErrors) || count != 1 || bulkErrors.OpErrors[0].Index != 1 {
+		t.Fatal("BulkFromChannel Error Failed", err, count)
+	}
+	fmt.Println("bulkFromChannel successful")
+}
+
+func Test_DryRun(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	logger := new(testLogger)
+	mog1.SetLogger(logger)
+	mog1.DryRun(true)
+	count, err := mog1.Update(m{"city": "Wonder"}, m{"$set": m{"city": "Gone"}})
+	if err != nil || count != 0 {
+		t.Fatal("DryRun Update Failed", err, count)
+	}
+	mog1.Insert(Property{Id: "p9"})
+	mog1.DeleteMany(m{"st": "NV"})
+	mog1.BulkStart(2)
+	mog1.BulkAddInsert(Property{Id: "p10"})
+	mog1.BulkAddDelete(bson.M{})
+	if count, err = mog1.BulkWrite(); err != nil || count != 0 {
+		t.Fatal("DryRun BulkWrite Failed", err, count)
+	}
+	mog1.Save(Property{Id: "p1", City: "Gone"})
+	mog1.UpdateId("p2", m{"$set": m{"city": "Gone"}})
+	if err = mog1.DeleteId("p3"); err != nil {
+		t.Fatal("DryRun DeleteId Failed", err)
+	}
+	var prop Property
+	if err = mog1.FindOneAndDelete(m{"_id": "p1"}, &prop); err != ErrDryRun {
+		t.Fatal("DryRun FindOneAndDelete Not Rejected", err)
+	}
+	batch := mog1.NewBulkBatch(1)
+	batch.AddInsert(Property{Id: "p11"})
+	if _, err = batch.Commit(); err != nil {
+		t.Fatal("DryRun BulkBatch Commit Failed", err)
+	}
+	if len(logger.messages) != 9 || !strings.Contains(logger.messages[0], "matched: 2") {
+		t.Fatal("DryRun Wrong Log Messages", logger.messages)
+	}
+	mog1.DryRun(false)
+	if count, _ = mog1.Count(m{"city": "Wonder"}); count != 2 {
+		t.Fatal("DryRun Updated Docs", count)
+	}
+	if count, _ = mog1.Count(bson.M{}); count != 3 {
+		t.Fatal("DryRun Inserted Or Deleted Docs", count)
+	}
+	fmt.Println("dryRun successful")
+}
+
+func Test_DeleteManyChunked(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	for i := 0; i < 25; i++ {
+		mog1.Insert(Property{Id: strconv.Itoa(i), City: "Wonder"})
+	}
+	mog1.Insert(Property{Id: "keep", City: "Las Vegas"})
+	logger := new(testLogger)
+	mog1.SetLogger(logger)
+	var progress []int64
+	deleted, err := mog1.DeleteManyChunked(m{"city": "Wonder"}, 10, time.Millisecond, func(deleted int64) {
+		progress = append(progress, deleted)
+	})
+	if err != nil || deleted != 25 {
+		t.Fatal("DeleteManyChunked Failed", err, deleted)
+	}
+	if len(logger.messages) != 3 {
+		t.Fatal("DeleteManyChunked Wrong Progress Messages", logger.messages)
+	}
+	if len(progress) != 3 || progress[2] != 25 {
+		t.Fatal("DeleteManyChunked Wrong Progress Reported", progress)
+	}
+	if count, _ := mog1.Count(bson.M{}); count != 1 {
+		t.Fatal("DeleteManyChunked Wrong Count Remaining", count)
+	}
+	if _, err = mog1.DeleteManyChunked(nil, 10, 0); err == nil {
+		t.Fatal("DeleteManyChunked Nil Criteria Did Not Fail")
+	}
+	fmt.Println("deleteManyChunked successful")
+}
+
+func Test_WithContext(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var props []Property
+	if err := mog1.WithContext(ctx).FindAll(nil, &props); !errors.Is(err, context.Canceled) {
+		t.Fatal("WithContext Canceled Context Not Used", err)
+	}
+	if err := mog1.FindAll(nil, &props); err != nil || len(props) != 3 {
+		t.Fatal("WithContext Changed Original Mog", err, len(props))
+	}
+	fmt.Println("withContext successful")
+}
+
+// uses a server address where nothing is listening, operations wait until timed out
+func Test_SetDefaultTimeout(t *testing.T) {
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:1/?serverSelectionTimeoutMS=30000"))
+	if err != nil {
+		t.Fatal("Mongo Connect Failed", err)
+	}
+	defer client.Disconnect(ctx)
+	mog1 := NewMog(ctx, client.Database("demo"), "property")
+
+	mog1.SetDefaultTimeout(100*time.Millisecond, 200*time.Millisecond)
+	start := time.Now()
+	if _, err = mog1.Count(m{}); !mongo.IsTimeout(err) {
+		t.Fatal("SetDefaultTimeout Read Not Timed Out", err)
+	}
+	if err = mog1.Clone().Insert(Property{Id: "p1"}); !mongo.IsTimeout(err) {
+		t.Fatal("SetDefaultTimeout Write Not Timed Out", err)
+	}
+	if err = mog1.FindUntil(nil, func(raw bson.Raw) (bool, error) { return true, nil }); !mongo.IsTimeout(err) {
+		t.Fatal("SetDefaultTimeout FindUntil Not Timed Out", err)
+	}
+	if _, findErr := mog1.FindChan(nil); !mongo.IsTimeout(findErr()) {
+		t.Fatal("SetDefaultTimeout FindChan Not Timed Out", findErr())
+	}
+	if _, err = mog1.Checksum(nil); !mongo.IsTimeout(err) {
+		t.Fatal("SetDefaultTimeout Checksum Not Timed Out", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatal("SetDefaultTimeout Too Slow", elapsed)
+	}
+	if mog1.ctx != ctx {
+		t.Fatal("SetDefaultTimeout Context Not Restored")
+	}
+	fmt.Println("setDefaultTimeout successful")
+}
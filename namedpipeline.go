@@ -0,0 +1,40 @@
+package mog
+
+import (
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// --- Named Pipeline Methods ----------------------------------------------------
+
+var (
+	pipelinesMu sync.RWMutex
+	pipelines   = make(map[string]func(*Mog))
+)
+
+// RegisterPipeline defines aggregation pipeline name, run using AggRunNamed.
+// Parm "build" adds the stages to mog.AggPipeline using Agg.. methods, reading parameters from mog.AggParams.
+// Registering an existing name replaces it. Safe for concurrent use.
+// Ex: RegisterPipeline("st_totals", func(mog *Mog) { mog.AggMatch(bson.M{"st": mog.AggParams["st"]}); mog.AggTotal("city") })
+func RegisterPipeline(name string, build func(*Mog)) {
+	pipelinesMu.Lock()
+	defer pipelinesMu.Unlock()
+	pipelines[name] = build
+}
+
+// AggRunNamed builds pipeline name (see RegisterPipeline) with mog.AggParams set to params, then runs it like AggRun.
+// Use mog.Next() to iterate thru the results. Returns error if name is not registered.
+func (mog *Mog) AggRunNamed(name string, params bson.M) error {
+	pipelinesMu.RLock()
+	build, found := pipelines[name]
+	pipelinesMu.RUnlock()
+	if !found {
+		return errors.New("pipeline not registered: " + name)
+	}
+	mog.AggStart()
+	mog.AggParams = params
+	build(mog)
+	return mog.AggRun()
+}
@@ -0,0 +1,38 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_AggRunNamed(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	RegisterPipeline("city_totals", func(mog *Mog) {
+		mog.AggMatch(bson.M{"st": mog.AggParams["st"]})
+		mog.AggTotal("city", "sum_fld1")
+		mog.AggSort("_id")
+	})
+	type cityTotal struct {
+		City       string `bson:"_id"`
+		TotSumFld1 int    `bson:"tot_sum_fld1"`
+	}
+	for st, want := range map[string]int{"MT": 17, "NV": 13} {
+		if err := mog1.AggRunNamed("city_totals", bson.M{"st": st}); err != nil {
+			t.Fatal("AggRunNamed Failed", err)
+		}
+		var total cityTotal
+		if !mog1.Next(&total) || total.TotSumFld1 != want {
+			t.Fatal("AggRunNamed Wrong Result", st, total, mog1.IterErr())
+		}
+		mog1.CloseIter()
+	}
+	if err := mog1.AggRunNamed("no_such_pipeline", nil); err == nil {
+		t.Fatal("AggRunNamed Unregistered Name Not Rejected")
+	}
+	fmt.Println("aggRunNamed successful")
+}
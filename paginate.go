@@ -0,0 +1,159 @@
+package mog
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// --- Pagination Methods ----------------------------------------------------
+
+// PageInfo describes a page of results returned by Paginate.
+type PageInfo struct {
+	TotalDocs  int64 `json:"totalDocs"`
+	TotalPages int64 `json:"totalPages"`
+	Page       int64 `json:"page"`
+	PerPage    int64 `json:"perPage"`
+	HasNext    bool  `json:"hasNext"`
+	HasPrev    bool  `json:"hasPrev"`
+}
+
+// Paginate loads docs with page number "page" (1 is 1st page) of docs matching criteria, perPage docs per page.
+// Parm "docs" should be address of target slice. Use sortFlds for a stable order across pages.
+// Returns PageInfo with total docs and pages for building page links.
+// Keep/Omit fields are honored. SetHint, SetCollation and SetMaxTime apply to both the count and the find.
+// For large collections see FindPage (keyset pagination).
+func (mog *Mog) Paginate(criteria interface{}, page, perPage int64, docs interface{}, sortFlds ...string) (PageInfo, error) {
+	if page < 1 || perPage < 1 {
+		return PageInfo{}, errors.New("page and perPage must be greater than 0")
+	}
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	hint, collation, maxTime := mog.hint, mog.collation, mog.maxTime // used by both count and find
+	mog.limit = 0                                                    // replaced by perPage, must not limit the count
+	total, err := mog.Count(criteria)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	info := PageInfo{
+		TotalDocs:  total,
+		TotalPages: (total + perPage - 1) / perPage,
+		Page:       page,
+		PerPage:    perPage,
+	}
+	info.HasNext = page < info.TotalPages
+	info.HasPrev = page > 1
+	mog.SetSkip((page - 1) * perPage)
+	mog.SetLimit(perPage)
+	mog.hint, mog.collation, mog.maxTime = hint, collation, maxTime
+	err = mog.FindAll(criteria, docs, sortFlds...)
+	return info, err
+}
+
+// FindPage loads docs with the next page (up to pageSize docs) of docs matching criteria, using keyset pagination.
+// Pass "" as token for the 1st page, then the returned nextToken for following pages. nextToken is "" after the last page.
+// Unlike Paginate, pages are located using the sort key values of the previous page's last doc,
+// so performance does not degrade on later pages and pages remain stable when docs are added.
+// The same criteria and sortFlds must be used for every page. _id is added as the final sort key if not present.
+// If Keep/Omit are used, sort fields must be included in results.
+func (mog *Mog) FindPage(criteria interface{}, pageSize int64, token string, docs interface{}, sortFlds ...string) (nextToken string, err error) {
+	if pageSize < 1 {
+		return "", errors.New("pageSize must be greater than 0")
+	}
+	hasId := false
+	for _, fld := range sortFlds {
+		if fld == "_id" || fld == "-_id" {
+			hasId = true
+		}
+	}
+	if !hasId {
+		sortFlds = append(sortFlds, "_id")
+	}
+	sortOrder := CreateSortOrder(sortFlds)
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	if token != "" {
+		values, err := decodePageToken(token, len(sortOrder))
+		if err != nil {
+			return "", err
+		}
+		criteria = bson.M{"$and": bson.A{criteria, keysetCriteria(sortOrder, values)}}
+	}
+	var raws []bson.Raw
+	mog.SetLimit(pageSize + 1) // 1 extra to know if there is another page
+	if err = mog.FindAll(criteria, &raws, sortFlds...); err != nil {
+		return "", err
+	}
+	if int64(len(raws)) > pageSize {
+		raws = raws[:pageSize]
+		if nextToken, err = encodePageToken(raws[len(raws)-1], sortOrder); err != nil {
+			return "", err
+		}
+	}
+	raw, err := bson.Marshal(bson.M{"docs": raws})
+	if err != nil {
+		return "", err
+	}
+	if err = bson.Raw(raw).Lookup("docs").Unmarshal(docs); err != nil {
+		return "", err
+	}
+	return nextToken, afterFindAll(docs)
+}
+
+// keysetCriteria returns criteria matching docs that sort after values.
+// For sort keys k1, k2, k3 it matches k1 after v1, or k1 = v1 and k2 after v2, or k1 = v1 and k2 = v2 and k3 after v3.
+func keysetCriteria(sortOrder bson.D, values []bson.RawValue) bson.M {
+	or := make(bson.A, len(sortOrder))
+	for i, key := range sortOrder {
+		clause := bson.D{}
+		for j := 0; j < i; j++ {
+			clause = append(clause, bson.E{Key: sortOrder[j].Key, Value: values[j]})
+		}
+		op := "$gt"
+		if key.Value == -1 {
+			op = "$lt"
+		}
+		clause = append(clause, bson.E{Key: key.Key, Value: bson.M{op: values[i]}})
+		or[i] = clause
+	}
+	return bson.M{"$or": or}
+}
+
+// encodePageToken returns an opaque token holding the sort key values of doc.
+func encodePageToken(doc bson.Raw, sortOrder bson.D) (string, error) {
+	values := make(bson.A, len(sortOrder))
+	for i, key := range sortOrder {
+		value, err := doc.LookupErr(strings.Split(key.Key, ".")...)
+		if err != nil {
+			return "", errors.New("sort field " + key.Key + " missing from result")
+		}
+		values[i] = value
+	}
+	raw, err := bson.Marshal(bson.M{"v": values})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodePageToken returns the sort key values held by token.
+func decodePageToken(token string, keyCount int) ([]bson.RawValue, error) {
+	invalid := errors.New("invalid page token")
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+	array, ok := bson.Raw(raw).Lookup("v").ArrayOK()
+	if !ok {
+		return nil, invalid
+	}
+	values, err := array.Values()
+	if err != nil || len(values) != keyCount {
+		return nil, invalid
+	}
+	return values, nil
+}
@@ -0,0 +1,60 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_Paginate(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	var props []Property
+	info, err := mog1.Paginate(nil, 2, 2, &props, "_id")
+	if err != nil || len(props) != 1 || props[0].Id != "p3" {
+		t.Fatal("Paginate Failed", err, props)
+	}
+	want := PageInfo{TotalDocs: 3, TotalPages: 2, Page: 2, PerPage: 2, HasNext: false, HasPrev: true}
+	if info != want {
+		t.Fatal("Paginate Wrong PageInfo", info)
+	}
+	info, _ = mog1.Paginate(nil, 1, 2, &props, "_id")
+	if len(props) != 2 || !info.HasNext {
+		t.Fatal("Paginate Page 1 Failed", info, props)
+	}
+	fmt.Println("paginate successful")
+}
+
+func Test_FindPage(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+	mog1.Insert(Property{Id: "p4", City: "Wonder", St: "MT"})
+
+	// pages sorted by city descending, then _id
+	var ids []string
+	token := ""
+	for pages := 0; pages < 5; pages++ {
+		var props []Property
+		nextToken, err := mog1.FindPage(nil, 3, token, &props, "-city")
+		if err != nil {
+			t.Fatal("FindPage Failed", err)
+		}
+		for _, prop := range props {
+			ids = append(ids, prop.Id)
+		}
+		if nextToken == "" {
+			break
+		}
+		token = nextToken
+	}
+	if fmt.Sprint(ids) != "[p1 p2 p4 p3]" {
+		t.Fatal("FindPage Wrong Results", ids)
+	}
+	var props []Property
+	if _, err := mog1.FindPage(nil, 3, "bogus!", &props); err == nil {
+		t.Fatal("FindPage Invalid Token Did Not Fail")
+	}
+	fmt.Println("findPage successful")
+}
@@ -0,0 +1,53 @@
+package mog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket used to pace write operations, see SetWriteRateLimit.
+// The bucket holds at most 1 second of operations. Requests larger than the available tokens
+// are allowed to go into debt and wait until the debt is repaid.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(opsPerSecond int) *rateLimiter {
+	return &rateLimiter{
+		rate:   float64(opsPerSecond),
+		tokens: float64(opsPerSecond),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until n operations are allowed or ctx is done.
+func (rl *rateLimiter) wait(ctx context.Context, n int) error {
+	rl.mu.Lock()
+	now := time.Now()
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+	if rl.tokens > rl.rate {
+		rl.tokens = rl.rate
+	}
+	rl.last = now
+	rl.tokens -= float64(n)
+	var delay time.Duration
+	if rl.tokens < 0 {
+		delay = time.Duration(-rl.tokens / rl.rate * float64(time.Second))
+	}
+	rl.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
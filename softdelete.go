@@ -0,0 +1,157 @@
+package mog
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// --- Soft Delete Methods ----------------------------------------------------
+
+// EnableSoftDelete turns on soft delete using field (such as "deleted_at"). Use "" to turn off.
+// DeleteOne, DeleteMany, DeleteId, DeleteManyChunked, Deduplicate, FindOneAndDelete, BulkAddDelete and BulkAddDeleteOne
+// set field to the current date instead of removing docs (bulk counts show them as modified, not deleted).
+// Find.. (including FindOneAndUpdate and FindOneAndReplace), Count, Exists, Distinct, TextSearch, Paginate,
+// DistinctWithCounts, Percentiles, Checksum, FindLatestPerGroup, ExplainFind, AggExplain and
+// Agg runs (AggRun, AggRunAll, AggRunCount, AggRunOn, AggCsvStream) skip docs having field set.
+// Use FindDeleted to load deleted docs and Restore to undelete them. Remains in effect until turned off.
+func (mog *Mog) EnableSoftDelete(field string) {
+	mog.softDeleteFld = field
+}
+
+// Restore undeletes docs matching criteria that were soft deleted. Returns count of docs restored.
+func (mog *Mog) Restore(criteria interface{}) (int64, error) {
+	if mog.softDeleteFld == "" {
+		return 0, errors.New("soft delete not enabled")
+	}
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	deleted := bson.M{"$and": bson.A{criteria, bson.M{mog.softDeleteFld: bson.M{"$ne": nil}}}}
+	restore := bson.M{"$unset": bson.M{mog.softDeleteFld: ""}}
+	if mog.dryRun {
+		return 0, mog.dryRunLog("restore", deleted, restore)
+	}
+	before, err := mog.auditBefore(deleted, true)
+	if err != nil {
+		return 0, err
+	}
+	if err = mog.waitToWrite(1); err != nil {
+		return 0, err
+	}
+	var result *mongo.UpdateResult
+	err = mog.retryWrite(func() error {
+		var err error
+		result, err = mog.collection.UpdateMany(mog.ctx, deleted, restore)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if result.ModifiedCount > 0 {
+		err = mog.auditLog("restore", criteria, []interface{}{restore}, before)
+	}
+	return result.ModifiedCount, err
+}
+
+// FindDeleted loads soft deleted docs matching criteria (nil for all) into docs, see FindAll.
+func (mog *Mog) FindDeleted(criteria interface{}, docs interface{}, sortFlds ...string) error {
+	if mog.softDeleteFld == "" {
+		return errors.New("soft delete not enabled")
+	}
+	findOptions := mog.findOptions(sortFlds)
+	if criteria == nil {
+		criteria = bson.D{}
+	}
+	deleted := bson.M{"$and": bson.A{criteria, bson.M{mog.softDeleteFld: bson.M{"$ne": nil}}}}
+	err := mog.retry(func() error {
+		cursor, err := mog.collection.Find(mog.ctx, deleted, findOptions)
+		if err != nil {
+			return err
+		}
+		return cursor.All(mog.ctx, docs)
+	})
+	if err != nil {
+		return err
+	}
+	return afterFindAll(docs)
+}
+
+// notDeleted returns criteria limited to docs not soft deleted. If soft delete is off, criteria is returned.
+func (mog *Mog) notDeleted(criteria interface{}) interface{} {
+	if mog.softDeleteFld == "" {
+		return criteria
+	}
+	if criteria == nil {
+		return bson.M{mog.softDeleteFld: nil}
+	}
+	return bson.M{"$and": bson.A{criteria, bson.M{mog.softDeleteFld: nil}}}
+}
+
+// notDeletedPipeline returns pipeline with a stage skipping soft deleted docs, if soft delete is on.
+// The stage is added after stages that must be 1st ($geoNear, $search, etc.).
+func (mog *Mog) notDeletedPipeline(pipeline []bson.M) []bson.M {
+	if mog.softDeleteFld == "" {
+		return pipeline
+	}
+	at := 0
+	if len(pipeline) > 0 {
+		for _, op := range []string{"$geoNear", "$search", "$searchMeta", "$vectorSearch", "$collStats", "$indexStats"} {
+			if pipeline[0][op] != nil {
+				at = 1
+			}
+		}
+	}
+	withMatch := make([]bson.M, 0, len(pipeline)+1)
+	withMatch = append(withMatch, pipeline[:at]...)
+	withMatch = append(withMatch, bson.M{"$match": bson.M{mog.softDeleteFld: nil}})
+	return append(withMatch, pipeline[at:]...)
+}
+
+// softDelete sets the soft delete field of the 1st (or all if many) docs matching criteria. Returns count of docs deleted.
+func (mog *Mog) softDelete(criteria interface{}, many bool) (int64, error) {
+	criteria = mog.notDeleted(criteria)
+	update := softDeleteUpdate(mog.softDeleteFld)
+	if err := mog.waitToWrite(1); err != nil {
+		return 0, err
+	}
+	var result *mongo.UpdateResult
+	err := mog.retryWrite(func() error {
+		var err error
+		if many {
+			result, err = mog.collection.UpdateMany(mog.ctx, criteria, update)
+		} else {
+			result, err = mog.collection.UpdateOne(mog.ctx, criteria, update)
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// softDeleteUpdate returns update setting field to the current date.
+func softDeleteUpdate(field string) bson.M {
+	return bson.M{"$currentDate": bson.M{field: true}}
+}
+
+// softDeleteOne works like FindOneAndDelete, setting the soft delete field instead of removing the doc.
+// Returns the doc found.
+func (mog *Mog) softDeleteOne(criteria, doc interface{}, opts *options.FindOneAndDeleteOptions) (bson.Raw, error) {
+	updateOpts := options.FindOneAndUpdate()
+	updateOpts.Sort = opts.Sort
+	updateOpts.Projection = opts.Projection
+	if err := mog.waitToWrite(1); err != nil {
+		return nil, err
+	}
+	var found bson.Raw
+	err := mog.retryWrite(func() error {
+		result := mog.collection.FindOneAndUpdate(mog.ctx, mog.notDeleted(criteria), softDeleteUpdate(mog.softDeleteFld), updateOpts)
+		found, _ = result.Raw()
+		return result.Decode(doc)
+	})
+	return found, err
+}
@@ -0,0 +1,65 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func Test_SoftDelete(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	mog1.EnableSoftDelete("deleted_at")
+	if count, err := mog1.DeleteMany(m{"city": "Wonder"}); err != nil || count != 2 {
+		t.Fatal("Soft DeleteMany Failed", err, count)
+	}
+	if count, _ := mog1.Count(nil); count != 1 {
+		t.Fatal("Count Includes Soft Deleted Docs", count)
+	}
+	var found Property
+	if err := mog1.FindOneAndUpdate(m{"_id": "p1"}, m{"$set": m{"st": "ID"}}, &found); err != mongo.ErrNoDocuments {
+		t.Fatal("FindOneAndUpdate Matched Soft Deleted Doc", err, found)
+	}
+	if err := mog1.FindOneAndReplace(m{"_id": "p1"}, Property{Id: "p1"}, &found); err != mongo.ErrNoDocuments {
+		t.Fatal("FindOneAndReplace Matched Soft Deleted Doc", err, found)
+	}
+	var props []Property
+	if err := mog1.FindAll(nil, &props); err != nil || len(props) != 1 || props[0].Id != "p3" {
+		t.Fatal("FindAll Includes Soft Deleted Docs", err, props)
+	}
+	mog1.AggMatch(m{})
+	if count, err := mog1.AggRunCount(); err != nil || count != 1 {
+		t.Fatal("AggRunCount Includes Soft Deleted Docs", err, count)
+	}
+	if err := mog1.FindDeleted(nil, &props, "_id"); err != nil || len(props) != 2 || props[0].Id != "p1" {
+		t.Fatal("FindDeleted Failed", err, props)
+	}
+	if err := mog1.DeleteId("p1"); err == nil {
+		t.Fatal("DeleteId Of Soft Deleted Doc Did Not Fail")
+	}
+	if count, err := mog1.Restore(m{"_id": "p1"}); err != nil || count != 1 {
+		t.Fatal("Restore Failed", err, count)
+	}
+	var prop Property
+	if err := mog1.FindId("p1", &prop); err != nil {
+		t.Fatal("Restored Doc Not Found", err)
+	}
+	if err := mog1.FindLatestPerGroup("city", "date_added", &props); err != nil || len(props) != 2 || props[1].Id != "p1" {
+		t.Fatal("FindLatestPerGroup Includes Soft Deleted Docs", err, props)
+	}
+	mog1.Insert(Property{Id: "p4", Address: "200 Willow Rd", City: "Wonder"})
+	if removed, err := mog1.Deduplicate("address", "city"); err != nil || removed != 1 {
+		t.Fatal("Soft Deduplicate Failed", err, removed)
+	}
+	if err := mog1.FindDeleted(m{"_id": "p4"}, &props); err != nil || len(props) != 1 {
+		t.Fatal("Deduplicate Did Not Soft Delete", err, props)
+	}
+	mog1.EnableSoftDelete("")
+	if count, _ := mog1.Count(m{}); count != 4 {
+		t.Fatal("Soft Deleted Docs Removed", count)
+	}
+	fmt.Println("soft delete successful")
+}
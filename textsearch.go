@@ -0,0 +1,49 @@
+package mog
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// --- Text Search Methods ----------------------------------------------------
+
+// TextScoreFld is the field TextSearch loads with each doc's relevance score.
+// Add a field with this bson name to the doc type to receive it, ex: Score float64 `bson:"score"`.
+const TextScoreFld = "score"
+
+// CreateTextIndex creates a text index on fields and returns its name. A collection can have only 1 text index.
+// Ex: CreateTextIndex("address", "notes")
+func (mog *Mog) CreateTextIndex(fields ...string) (string, error) {
+	keys := make(bson.D, len(fields))
+	for i, field := range fields {
+		keys[i] = bson.E{Key: field, Value: "text"}
+	}
+	return mog.CreateIndex(keys, IndexOpts{})
+}
+
+// TextSearch loads docs with docs matching phrase using the collection's text index, most relevant first.
+// Parm "phrase" uses $text $search syntax: words are or'd, "quoted phrase" must match, -word excludes.
+// Each doc's relevance score is loaded into field TextScoreFld.
+// Settings used by FindAll (Keep/Omit, SetLimit, etc.) are applied and reset.
+// Ex: TextSearch("willow \"angel way\"", &props)
+func (mog *Mog) TextSearch(phrase string, docs interface{}) error {
+	findOptions := mog.findOptions(nil)
+	score := bson.M{"$meta": "textScore"}
+	projection := bson.M{TextScoreFld: score}
+	for fld, val := range mog.projectFlds {
+		projection[fld] = val
+	}
+	findOptions.SetProjection(projection)
+	findOptions.SetSort(bson.D{{Key: TextScoreFld, Value: score}})
+	criteria := mog.notDeleted(bson.M{"$text": bson.M{"$search": phrase}})
+	err := mog.retry(func() error {
+		cursor, err := mog.collection.Find(mog.ctx, criteria, findOptions)
+		if err != nil {
+			return err
+		}
+		return cursor.All(mog.ctx, docs)
+	})
+	if err != nil {
+		return err
+	}
+	return afterFindAll(docs)
+}
@@ -0,0 +1,32 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_TextSearch(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	if _, err := mog1.CreateTextIndex("address", "city"); err != nil {
+		t.Fatal("CreateTextIndex Failed", err)
+	}
+	var results []struct {
+		Id    string  `bson:"_id"`
+		City  string  `bson:"city"`
+		Score float64 `bson:"score"`
+	}
+	mog1.Keep("city")
+	if err := mog1.TextSearch("wonder willow", &results); err != nil || len(results) != 2 {
+		t.Fatal("TextSearch Failed", err, results)
+	}
+	if results[0].Id != "p1" || results[0].Score <= results[1].Score || results[0].City != "Wonder" {
+		t.Fatal("TextSearch Score Order Failed", results)
+	}
+	if err := mog1.TextSearch("wonder -angel", &results); err != nil || len(results) != 1 || results[0].Id != "p1" {
+		t.Fatal("TextSearch Exclude Failed", err, results)
+	}
+	fmt.Println("textSearch successful")
+}
@@ -0,0 +1,115 @@
+package mog
+
+import (
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// --- Timestamp Methods ----------------------------------------------------
+
+// Field names set by AutoTimestamps.
+const (
+	CreatedAtFld = "created_at"
+	UpdatedAtFld = "updated_at"
+)
+
+// AutoTimestamps turns automatic timestamps on or off. When on:
+// Insert, BulkAddInsert, BulkFromChannel, BulkBatch.AddInsert and BulkWriter.Add set created_at (unless already set)
+// and updated_at of each doc.
+// Update, UpdateId, UpdateVersioned, FindOneAndUpdate, BulkAddUpdate, BulkAddUpdateOne and BulkBatch.AddUpdate set
+// updated_at using $currentDate ($$NOW for pipeline updates).
+// Replace, Save, ReplaceVersioned, FindOneAndReplace and BulkAddReplace set updated_at of the new doc,
+// created_at is kept only if the new doc has it.
+// BulkBatch and BulkWriter use the setting of mog when they were created.
+// Docs are converted to bson.D to add the fields, the caller's docs are not changed.
+// Remains in effect until AutoTimestamps(false) is called.
+func (mog *Mog) AutoTimestamps(on bool) {
+	mog.autoTimestamps = on
+}
+
+// timestampDoc returns doc as bson.D with updated_at set to now, and created_at set to now if missing or zero.
+// If created is false, created_at is not changed.
+func timestampDoc(doc interface{}, created bool) (interface{}, error) {
+	d, err := asDoc(doc)
+	if err != nil {
+		return doc, err
+	}
+	now := time.Now()
+	if created {
+		d = setTimestamp(d, CreatedAtFld, now, false)
+	}
+	return setTimestamp(d, UpdatedAtFld, now, true), nil
+}
+
+// setTimestamp sets fld of d to now. Existing non-zero values are only replaced if replace is true.
+func setTimestamp(d bson.D, fld string, now time.Time, replace bool) bson.D {
+	for i, elem := range d {
+		if elem.Key != fld {
+			continue
+		}
+		if replace || elem.Value == nil || isZeroDateTime(elem.Value) {
+			d[i].Value = now
+		}
+		return d
+	}
+	return append(d, bson.E{Key: fld, Value: now})
+}
+
+// isZeroDateTime returns true if val is the zero time.Time, as decoded into a bson.D.
+func isZeroDateTime(val interface{}) bool {
+	dateTime, ok := val.(interface{ Time() time.Time })
+	return ok && dateTime.Time().Equal(time.Time{})
+}
+
+// timestampUpdate returns update with updated_at set to the current date.
+// Update docs get $currentDate, pipeline updates get a $set stage using $$NOW.
+func timestampUpdate(update interface{}) (interface{}, error) {
+	if pipeline, ok := asPipeline(update); ok {
+		return append(pipeline, bson.M{"$set": bson.M{UpdatedAtFld: "$$NOW"}}), nil
+	}
+	d, err := asDoc(update)
+	if err != nil {
+		return update, err
+	}
+	for _, elem := range d { // leave update alone if it already sets updated_at
+		operatorDoc, _ := elem.Value.(bson.D)
+		for _, opElem := range operatorDoc {
+			if opElem.Key == UpdatedAtFld {
+				return d, nil
+			}
+		}
+	}
+	for i, elem := range d {
+		if currentDate, ok := elem.Value.(bson.D); ok && elem.Key == "$currentDate" {
+			d[i].Value = append(currentDate, bson.E{Key: UpdatedAtFld, Value: true})
+			return d, nil
+		}
+	}
+	return append(d, bson.E{Key: "$currentDate", Value: bson.D{{Key: UpdatedAtFld, Value: true}}}), nil
+}
+
+// asDoc returns doc (struct, map, bson.D, etc.) converted to bson.D.
+func asDoc(doc interface{}) (bson.D, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var d bson.D
+	err = bson.Unmarshal(raw, &d)
+	return d, err
+}
+
+// asPipeline returns update as bson.A with room for 1 more stage, if update is a pipeline (slice of stages).
+func asPipeline(update interface{}) (bson.A, bool) {
+	updateValue := reflect.ValueOf(update)
+	if _, isDoc := update.(bson.D); isDoc || updateValue.Kind() != reflect.Slice || updateValue.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false // bson.D and bson.Raw are docs
+	}
+	pipeline := make(bson.A, 0, updateValue.Len()+1)
+	for i := 0; i < updateValue.Len(); i++ {
+		pipeline = append(pipeline, updateValue.Index(i).Interface())
+	}
+	return pipeline, true
+}
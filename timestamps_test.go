@@ -0,0 +1,78 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type stampedProperty struct {
+	Id        string    `bson:"_id"`
+	City      string    `bson:"city"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func Test_AutoTimestamps(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	mog1.AutoTimestamps(true)
+	start := time.Now().Add(-time.Second)
+	if err := mog1.Insert(stampedProperty{Id: "p1", City: "Wonder"}, m{"_id": "p2", "city": "Wonder"}); err != nil {
+		t.Fatal("Insert With AutoTimestamps Failed", err)
+	}
+	var prop stampedProperty
+	mog1.FindId("p2", &prop)
+	if prop.CreatedAt.Before(start) || !prop.UpdatedAt.Equal(prop.CreatedAt) {
+		t.Fatal("Insert Timestamps Not Set", prop)
+	}
+	created := prop.CreatedAt
+	time.Sleep(10 * time.Millisecond)
+	if _, err := mog1.Update(m{"_id": "p2"}, m{"$set": m{"city": "Las Vegas"}}); err != nil {
+		t.Fatal("Update With AutoTimestamps Failed", err)
+	}
+	if _, err := mog1.Update(m{"_id": "p1"}, []bson.M{{"$set": m{"city": "Reno"}}}); err != nil {
+		t.Fatal("Pipeline Update With AutoTimestamps Failed", err)
+	}
+	var props []stampedProperty
+	mog1.FindAll(nil, &props, "_id")
+	for _, prop := range props {
+		if !prop.UpdatedAt.After(prop.CreatedAt) || prop.CreatedAt.Before(start) {
+			t.Fatal("Update Timestamps Wrong", prop)
+		}
+	}
+	prop.City = "Boise"
+	if err := mog1.Replace(m{"_id": "p2"}, prop); err != nil {
+		t.Fatal("Replace With AutoTimestamps Failed", err)
+	}
+	mog1.FindId("p2", &prop)
+	if !prop.CreatedAt.Equal(created) || !prop.UpdatedAt.After(created) {
+		t.Fatal("Replace Timestamps Wrong", prop)
+	}
+	batch := mog1.NewBulkBatch(1)
+	batch.AddInsert(stampedProperty{Id: "p3"})
+	if _, err := batch.Commit(); err != nil {
+		t.Fatal("BulkBatch With AutoTimestamps Failed", err)
+	}
+	writer := NewBulkWriter(mog1, 1, 10)
+	writer.Add(m{"_id": "p4"})
+	if _, err := writer.Close(); err != nil {
+		t.Fatal("BulkWriter With AutoTimestamps Failed", err)
+	}
+	for _, id := range []string{"p3", "p4"} {
+		mog1.FindId(id, &prop)
+		if prop.CreatedAt.Before(start) || prop.UpdatedAt.Before(start) {
+			t.Fatal("Bulk Insert Timestamps Not Set", prop)
+		}
+	}
+	updated := prop.UpdatedAt
+	time.Sleep(10 * time.Millisecond)
+	mog1.ReturnAfter()
+	if err := mog1.FindOneAndUpdate(m{"_id": "p4"}, m{"$set": m{"city": "Helena"}}, &prop); err != nil || !prop.UpdatedAt.After(updated) {
+		t.Fatal("FindOneAndUpdate Timestamps Wrong", err, prop)
+	}
+	fmt.Println("autoTimestamps successful")
+}
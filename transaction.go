@@ -0,0 +1,24 @@
+package mog
+
+import (
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithTransaction runs fn inside a transaction, committing if fn returns nil and aborting if it returns an error.
+// Parm "txMog" passed to fn is a clone of mog (see Clone) bound to the transaction, use it for all
+// operations that should be part of the transaction. Use txMog.SetCollection to work with multiple collections.
+// Transactions require a replica set or sharded cluster. fn may be run more than once if a
+// transient error occurs, so it should not have side effects outside the database.
+func (mog *Mog) WithTransaction(fn func(txMog *Mog) error) error {
+	session, err := mog.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(mog.ctx)
+	_, err = session.WithTransaction(mog.ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		txMog := mog.Clone()
+		txMog.ctx = sessCtx
+		return nil, fn(txMog)
+	})
+	return err
+}
@@ -0,0 +1,49 @@
+package mog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func Test_WithTransaction(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	testReplicaSet(t, mog1)
+	mog1.db.Collection("location").Drop(mog1.ctx)
+	mog1.db.CreateCollection(mog1.ctx, "location")
+
+	// committed transaction
+	err := mog1.WithTransaction(func(txMog *Mog) error {
+		if err := txMog.UpdateId("p1", m{"$set": m{"location_id": "20"}}); err != nil {
+			return err
+		}
+		txMog.SetCollection("location")
+		return txMog.Insert(Location{Id: "20", LocationName: "North"})
+	})
+	if err != nil {
+		t.Fatal("WithTransaction Commit Failed", err)
+	}
+	var prop Property
+	mog1.FindId("p1", &prop)
+	if prop.LocationId != "20" {
+		t.Fatal("WithTransaction Did Not Commit", prop)
+	}
+
+	// aborted transaction
+	errAbort := errors.New("abort")
+	err = mog1.WithTransaction(func(txMog *Mog) error {
+		txMog.UpdateId("p2", m{"$set": m{"location_id": "20"}})
+		return errAbort
+	})
+	if err != errAbort {
+		t.Fatal("WithTransaction Abort Wrong Error", err)
+	}
+	mog1.FindId("p2", &prop)
+	if prop.LocationId != "7" {
+		t.Fatal("WithTransaction Did Not Abort", prop)
+	}
+	fmt.Println("withTransaction successful")
+}
@@ -0,0 +1,61 @@
+package mog
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MogT is a Mog whose results are decoded into type T, removing the need for interface{} targets.
+// All Mog methods are available, FindAll, FindOne, FindId, Next and Insert are replaced by typed versions.
+// Create using NewTypedMog.
+type MogT[T any] struct {
+	*Mog
+}
+
+// NewTypedMog creates instance of MogT for collectionName, docs are decoded into type T.
+func NewTypedMog[T any](ctx context.Context, db *mongo.Database, collectionName string) *MogT[T] {
+	return &MogT[T]{Mog: NewMog(ctx, db, collectionName)}
+}
+
+// FindAll returns all docs matching criteria. Otherwise, works same as Mog.FindAll.
+func (mt *MogT[T]) FindAll(criteria interface{}, sortFlds ...string) ([]T, error) {
+	docs := make([]T, 0)
+	err := mt.Mog.FindAll(criteria, &docs, sortFlds...)
+	return docs, err
+}
+
+// FindOne returns the 1st doc found based on criteria and sort order.
+// If error == mongo.ErrNoDocuments, no docs found matching criteria.
+func (mt *MogT[T]) FindOne(criteria interface{}, sortFlds ...string) (T, error) {
+	var doc T
+	err := mt.Mog.FindOne(criteria, &doc, sortFlds...)
+	return doc, err
+}
+
+// FindId returns doc with matching _id.
+func (mt *MogT[T]) FindId(docId interface{}) (T, error) {
+	var doc T
+	err := mt.Mog.FindId(docId, &doc)
+	return doc, err
+}
+
+// Next returns the next doc from results of previously run Find(), and true if a doc was returned.
+// Works same as Mog.Next, use IterErr() after completion.
+func (mt *MogT[T]) Next() (T, bool) {
+	var doc T
+	more := mt.Mog.Next(&doc)
+	return doc, more
+}
+
+// Insert adds docs to collection.
+func (mt *MogT[T]) Insert(docs []T) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	items := make([]interface{}, len(docs))
+	for i := range docs {
+		items[i] = &docs[i] // address, so hooks with pointer receivers are found
+	}
+	return mt.Mog.Insert(items...)
+}
@@ -0,0 +1,65 @@
+package mog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func Test_TypedMog(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+
+	propMog := NewTypedMog[Property](mog1.ctx, mog1.db, "property")
+	err := propMog.Insert([]Property{
+		{Id: "t1", Address: "200 Willow Rd", City: "Wonder", St: "MT"},
+		{Id: "t2", Address: "1950 Hangover", City: "Las Vegas", St: "NV"},
+	})
+	if err != nil {
+		t.Fatal("Typed Insert Failed", err)
+	}
+	props, err := propMog.FindAll(nil, "_id")
+	if err != nil || len(props) != 2 || props[1].City != "Las Vegas" {
+		t.Fatal("Typed FindAll Failed", err, props)
+	}
+	prop, err := propMog.FindOne(m{"st": "MT"})
+	if err != nil || prop.Id != "t1" {
+		t.Fatal("Typed FindOne Failed", err, prop)
+	}
+	propMog.Find(nil, "-_id")
+	var ids []string
+	for prop, more := propMog.Next(); more; prop, more = propMog.Next() {
+		ids = append(ids, prop.Id)
+	}
+	if propMog.IterErr() != nil || len(ids) != 2 || ids[0] != "t2" {
+		t.Fatal("Typed Next Failed", propMog.IterErr(), ids)
+	}
+	fmt.Println("typed mog successful")
+}
+
+func Test_FindChan(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testProps(t, mog1)
+
+	propMog := NewTypedMog[Property](mog1.ctx, mog1.db, "property")
+	props, findErr := propMog.FindChan(m{"st": "MT"}, "_id")
+	var ids []string
+	for prop := range props {
+		ids = append(ids, prop.Id)
+	}
+	if err := findErr(); err != nil || len(ids) != 2 || ids[0] != "p1" {
+		t.Fatal("FindChan Failed", err, ids)
+	}
+
+	// cancelling context ends the query
+	ctx, cancel := context.WithCancel(mog1.ctx)
+	mogCancel := NewMog(ctx, mog1.db, "property")
+	raws, findErr := mogCancel.FindChan(nil)
+	<-raws
+	cancel() // goroutine is blocked sending 2nd doc
+	if err := findErr(); err != context.Canceled {
+		t.Fatal("FindChan Cancel Failed", err)
+	}
+	fmt.Println("findChan successful")
+}
@@ -0,0 +1,181 @@
+package mog
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// --- Versioned Methods ----------------------------------------------------
+
+// ErrVersionConflict is returned by UpdateVersioned and ReplaceVersioned when the doc's version in the collection
+// is not the version of the doc being written (it was changed by someone else since read), or the doc no longer exists.
+var ErrVersionConflict = errors.New("version conflict, doc changed or deleted since read")
+
+// UpdateVersioned updates the doc having the _id and version of doc, using update, and increments the version.
+// Parm "doc" is the address of a struct with an _id field and an integer field tagged `mog:"version"`.
+// On success the version field of doc is incremented, other fields of doc are not changed.
+// If no doc has the _id and version, ErrVersionConflict is returned. Read the doc again and retry.
+// SetHint and SetCollation are honored, Upsert is ignored (a missing doc is a conflict). All are reset.
+// Ex: err := mog.UpdateVersioned(&account, bson.M{"$inc": bson.M{"balance": -50}})
+func (mog *Mog) UpdateVersioned(doc, update interface{}) error {
+	version, versionFld, criteria, err := versionInfo(doc)
+	if err != nil {
+		return err
+	}
+	if err = beforeUpdate(update); err != nil {
+		return err
+	}
+	if mog.autoTimestamps {
+		if update, err = timestampUpdate(update); err != nil {
+			return err
+		}
+	}
+	if update, err = versionUpdate(update, versionFld); err != nil {
+		return err
+	}
+	updateOptions := mog.updateOptions().SetUpsert(false)
+	if mog.dryRun {
+		return mog.dryRunLog("update versioned", criteria, update)
+	}
+	before, err := mog.auditBefore(criteria, false)
+	if err != nil {
+		return err
+	}
+	if err = mog.waitToWrite(1); err != nil {
+		return err
+	}
+	var result *mongo.UpdateResult
+	err = mog.retryWrite(func() error {
+		var err error
+		result, err = mog.collection.UpdateOne(mog.ctx, criteria, update, updateOptions)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+	version.SetInt(version.Int() + 1)
+	return mog.auditLog("update", criteria, []interface{}{update}, before)
+}
+
+// ReplaceVersioned replaces the doc having the _id and version of doc with doc, incrementing the version.
+// Parm "doc" is the address of a struct with an _id field and an integer field tagged `mog:"version"`.
+// On success the version field of doc is incremented.
+// If no doc has the _id and version, ErrVersionConflict is returned. Read the doc again and retry.
+// Upsert is ignored (a missing doc is a conflict) and reset.
+func (mog *Mog) ReplaceVersioned(doc interface{}) error {
+	version, _, criteria, err := versionInfo(doc)
+	if err != nil {
+		return err
+	}
+	mog.upsert = false
+	oldVersion := version.Int()
+	version.SetInt(oldVersion + 1)
+	matched, err := mog.replaceOne(criteria, doc, options.Replace())
+	if err == nil && matched == 0 && !mog.dryRun {
+		err = ErrVersionConflict
+	}
+	if err != nil || mog.dryRun {
+		version.SetInt(oldVersion)
+	}
+	return err
+}
+
+// replaceOne replaces the doc matching criteria with newDoc using opts, honoring hooks, AutoTimestamps, DryRun
+// and EnableAudit. Returns count of docs matched.
+func (mog *Mog) replaceOne(criteria, newDoc interface{}, opts *options.ReplaceOptions) (int64, error) {
+	if err := beforeUpdate(newDoc); err != nil {
+		return 0, err
+	}
+	if mog.autoTimestamps {
+		var err error
+		if newDoc, err = timestampDoc(newDoc, false); err != nil {
+			return 0, err
+		}
+	}
+	if mog.dryRun {
+		return 0, mog.dryRunLog("replace", criteria, newDoc)
+	}
+	before, err := mog.auditBefore(criteria, false)
+	if err != nil {
+		return 0, err
+	}
+	if err = mog.waitToWrite(1); err != nil {
+		return 0, err
+	}
+	var result *mongo.UpdateResult
+	err = mog.retryWrite(func() error {
+		var err error
+		result, err = mog.collection.ReplaceOne(mog.ctx, criteria, newDoc, opts)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if result.MatchedCount+result.UpsertedCount > 0 {
+		err = mog.auditLog("replace", criteria, []interface{}{newDoc}, before)
+	}
+	return result.MatchedCount, err
+}
+
+// versionInfo returns the version field of doc, its bson name, and criteria matching the doc's _id and version.
+func versionInfo(doc interface{}) (reflect.Value, string, bson.D, error) {
+	docValue := reflect.ValueOf(doc)
+	if docValue.Kind() != reflect.Ptr || docValue.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, "", nil, errors.New("versioned doc must be address of struct")
+	}
+	docValue = docValue.Elem()
+	docType := docValue.Type()
+	for i := 0; i < docType.NumField(); i++ {
+		fld := docType.Field(i)
+		if fld.Tag.Get("mog") != "version" {
+			continue
+		}
+		version := docValue.Field(i)
+		switch version.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		default:
+			return reflect.Value{}, "", nil, errors.New("version field must be int type: " + fld.Name)
+		}
+		versionFld := strings.Split(fld.Tag.Get("bson"), ",")[0]
+		if versionFld == "" {
+			versionFld = strings.ToLower(fld.Name)
+		}
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			return reflect.Value{}, "", nil, err
+		}
+		docId, err := bson.Raw(raw).LookupErr("_id")
+		if err != nil {
+			return reflect.Value{}, "", nil, errors.New("versioned doc has no _id")
+		}
+		criteria := bson.D{{Key: "_id", Value: docId}, {Key: versionFld, Value: version.Int()}}
+		return version, versionFld, criteria, nil
+	}
+	return reflect.Value{}, "", nil, errors.New("doc has no field tagged mog:\"version\"")
+}
+
+// versionUpdate returns update with versionFld incremented, using $inc ($add for pipeline updates).
+func versionUpdate(update interface{}, versionFld string) (interface{}, error) {
+	if pipeline, ok := asPipeline(update); ok {
+		return append(pipeline, bson.M{"$set": bson.M{versionFld: bson.M{"$add": bson.A{"$" + versionFld, 1}}}}), nil
+	}
+	d, err := asDoc(update)
+	if err != nil {
+		return update, err
+	}
+	for i, elem := range d {
+		if inc, ok := elem.Value.(bson.D); ok && elem.Key == "$inc" {
+			d[i].Value = append(inc, bson.E{Key: versionFld, Value: 1})
+			return d, nil
+		}
+	}
+	return append(d, bson.E{Key: "$inc", Value: bson.D{{Key: versionFld, Value: 1}}}), nil
+}
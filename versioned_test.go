@@ -0,0 +1,53 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+)
+
+type versionedAccount struct {
+	Id      string `bson:"_id"`
+	Balance int    `bson:"balance"`
+	Version int    `bson:"version" mog:"version"`
+}
+
+func Test_Versioned(t *testing.T) {
+	mog1, disconnect := testMog(t, "account")
+	defer disconnect()
+	mog1.Insert(versionedAccount{Id: "a1", Balance: 100})
+
+	var reader1, reader2 versionedAccount
+	mog1.FindId("a1", &reader1)
+	mog1.FindId("a1", &reader2)
+	if err := mog1.UpdateVersioned(&reader1, m{"$inc": m{"balance": -50}}); err != nil || reader1.Version != 1 {
+		t.Fatal("UpdateVersioned Failed", err, reader1)
+	}
+	if err := mog1.UpdateVersioned(&reader2, m{"$inc": m{"balance": -80}}); err != ErrVersionConflict || reader2.Version != 0 {
+		t.Fatal("UpdateVersioned Conflict Not Detected", err, reader2)
+	}
+	reader2.Balance = 0
+	if err := mog1.ReplaceVersioned(&reader2); err != ErrVersionConflict || reader2.Version != 0 {
+		t.Fatal("ReplaceVersioned Conflict Not Detected", err, reader2)
+	}
+	reader1.Balance = 40
+	if err := mog1.ReplaceVersioned(&reader1); err != nil || reader1.Version != 2 {
+		t.Fatal("ReplaceVersioned Failed", err, reader1)
+	}
+	var account versionedAccount
+	mog1.FindId("a1", &account)
+	if account != reader1 {
+		t.Fatal("Versioned Writes Wrong Result", account)
+	}
+	mog1.Upsert() // must not insert a missing doc
+	missing := versionedAccount{Id: "a2"}
+	if err := mog1.UpdateVersioned(&missing, m{"$set": m{"balance": 10}}); err != ErrVersionConflict {
+		t.Fatal("UpdateVersioned Missing Doc Not A Conflict", err)
+	}
+	if count, _ := mog1.Count(m{"_id": "a2"}); count != 0 {
+		t.Fatal("UpdateVersioned Upserted Missing Doc", count)
+	}
+	if err := mog1.UpdateVersioned(&Property{Id: "p1"}, m{}); err == nil {
+		t.Fatal("UpdateVersioned Without Version Field Did Not Fail")
+	}
+	fmt.Println("versioned successful")
+}
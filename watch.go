@@ -0,0 +1,143 @@
+package mog
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// --- Change Stream Methods ----------------------------------------------------
+
+// ChangeEvent is a change stream event, decode events into it using WatchNext.
+// See MongoDB change events doc for all fields, use a custom type to decode others.
+type ChangeEvent struct {
+	Id                bson.Raw `bson:"_id"` // resume token
+	OperationType     string   `bson:"operationType"`
+	DocumentKey       bson.Raw `bson:"documentKey"`
+	FullDocument      bson.Raw `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.Raw `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+// Decode loads doc with the event's fullDocument.
+// For updates, fullDocument is the current version of the doc (looked up when the event is read).
+// Delete events have no fullDocument.
+func (event *ChangeEvent) Decode(doc interface{}) error {
+	if len(event.FullDocument) == 0 {
+		return errors.New("change event has no fullDocument")
+	}
+	return bson.Unmarshal(event.FullDocument, doc)
+}
+
+// ResumeTokenStore saves and loads change stream resume tokens, allowing a watcher to continue
+// where it left off after a restart. See SetResumeTokenStore and NewCollectionTokenStore.
+type ResumeTokenStore interface {
+	Load(streamName string) (bson.Raw, error) // returns nil token if none saved
+	Save(streamName string, token bson.Raw) error
+}
+
+// SetResumeTokenStore makes WatchStart resume after the token saved in store under streamName,
+// and WatchNext save the token of each event returned. Use a unique streamName for each watcher.
+// Pass nil store to stop using a store.
+func (mog *Mog) SetResumeTokenStore(store ResumeTokenStore, streamName string) {
+	mog.tokenStore = store
+	mog.streamName = streamName
+}
+
+// collectionTokenStore is a ResumeTokenStore keeping tokens in a collection.
+type collectionTokenStore struct {
+	mog *Mog
+}
+
+// NewCollectionTokenStore returns a ResumeTokenStore keeping tokens in collectionName of mog's database.
+// Each stream is stored as a doc with _id = streamName.
+func NewCollectionTokenStore(mog *Mog, collectionName string) ResumeTokenStore {
+	store := collectionTokenStore{mog: mog.Clone()}
+	store.mog.SetCollection(collectionName)
+	return store
+}
+
+func (store collectionTokenStore) Load(streamName string) (bson.Raw, error) {
+	var doc struct {
+		Token bson.Raw `bson:"token"`
+	}
+	err := store.mog.FindId(streamName, &doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return doc.Token, err
+}
+
+func (store collectionTokenStore) Save(streamName string, token bson.Raw) error {
+	return store.mog.Save(bson.M{"_id": streamName, "token": token, "saved_at": time.Now()})
+}
+
+// WatchStart opens a change stream on the collection, use WatchNext to iterate thru the events.
+// Optional pipeline stages filter or reshape events, e.g. bson.M{"$match": bson.M{"operationType": "insert"}}.
+// If a resume token store is set (see SetResumeTokenStore), the stream starts after the saved token.
+// Change streams require a replica set or sharded cluster.
+func (mog *Mog) WatchStart(pipeline ...bson.M) error {
+	if pipeline == nil {
+		pipeline = []bson.M{}
+	}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if mog.tokenStore != nil {
+		token, err := mog.tokenStore.Load(mog.streamName)
+		if err != nil {
+			mog.streamErr = err
+			return err
+		}
+		if token != nil {
+			opts.SetStartAfter(token)
+		}
+	}
+	stream, err := mog.collection.Watch(mog.ctx, pipeline, opts)
+	mog.stream = stream
+	mog.streamErr = err
+	return err
+}
+
+// WatchNext waits for the next change event and loads it into event (typically *ChangeEvent).
+// Returns false if the stream is closed, mog's context is done, or an error occurs. Use WatchErr() to get the error.
+// If a resume token store is set, the event's token is saved before returning.
+func (mog *Mog) WatchNext(event interface{}) bool {
+	if mog.stream == nil {
+		return false
+	}
+	if !mog.stream.Next(mog.ctx) {
+		mog.streamErr = mog.stream.Err()
+		return false
+	}
+	if err := mog.stream.Decode(event); err != nil {
+		mog.log().Error("mog.WatchNext decode error "+mog.collectionName, err)
+		mog.streamErr = err
+		return false
+	}
+	if mog.tokenStore != nil {
+		if err := mog.tokenStore.Save(mog.streamName, mog.stream.ResumeToken()); err != nil {
+			mog.streamErr = err
+			return false
+		}
+	}
+	return true
+}
+
+// WatchErr returns the error that ended WatchStart or WatchNext.
+func (mog *Mog) WatchErr() error {
+	return mog.streamErr
+}
+
+// WatchClose closes the change stream.
+func (mog *Mog) WatchClose() error {
+	if mog.stream == nil {
+		return nil
+	}
+	err := mog.stream.Close(mog.ctx)
+	mog.stream = nil
+	return err
+}
@@ -0,0 +1,79 @@
+package mog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_Watch(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testReplicaSet(t, mog1)
+	mog1.db.CreateCollection(mog1.ctx, "property")
+
+	if err := mog1.WatchStart(bson.M{"$match": bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update"}}}}); err != nil {
+		t.Fatal("WatchStart Failed", err)
+	}
+	defer mog1.WatchClose()
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		writer := mog1.Clone()
+		writer.Insert(Property{Id: "w1", City: "Wonder"})
+		writer.UpdateId("w1", m{"$set": m{"city": "Okobear"}})
+	}()
+
+	var event ChangeEvent
+	var prop Property
+	var cities []string
+	for len(cities) < 2 && mog1.WatchNext(&event) {
+		if err := event.Decode(&prop); err != nil {
+			t.Fatal("ChangeEvent Decode Failed", err)
+		}
+		cities = append(cities, event.OperationType+":"+prop.City)
+	}
+	if mog1.WatchErr() != nil || fmt.Sprint(cities) != "[insert:Wonder update:Okobear]" {
+		t.Fatal("Watch Failed", mog1.WatchErr(), cities)
+	}
+	fmt.Println("watch successful")
+}
+
+func Test_WatchResume(t *testing.T) {
+	mog1, disconnect := testMog(t, "property")
+	defer disconnect()
+	testReplicaSet(t, mog1)
+	mog1.db.CreateCollection(mog1.ctx, "property")
+	mog1.db.Collection("watch_tokens").Drop(mog1.ctx)
+	store := NewCollectionTokenStore(mog1, "watch_tokens")
+
+	// 1st watcher reads 1 event then stops
+	watcher := mog1.Clone()
+	watcher.SetResumeTokenStore(store, "props")
+	watcher.WatchStart()
+	mog1.Insert(Property{Id: "r1"})
+	var event ChangeEvent
+	var prop Property
+	if !watcher.WatchNext(&event) {
+		t.Fatal("WatchNext Failed", watcher.WatchErr())
+	}
+	watcher.WatchClose()
+
+	// events while no watcher running
+	mog1.Insert(Property{Id: "r2"})
+
+	// new watcher resumes after r1
+	watcher = mog1.Clone()
+	watcher.SetResumeTokenStore(store, "props")
+	watcher.WatchStart()
+	defer watcher.WatchClose()
+	if !watcher.WatchNext(&event) {
+		t.Fatal("WatchNext After Resume Failed", watcher.WatchErr())
+	}
+	event.Decode(&prop)
+	if prop.Id != "r2" {
+		t.Fatal("Watch Did Not Resume", prop.Id)
+	}
+	fmt.Println("watch resume successful")
+}